@@ -0,0 +1,117 @@
+package ltsvlog
+
+import (
+	"encoding/base64"
+	"errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidQuotedBytes is the error returned from DecodeQuotedBytes when
+// the value has an invalid escape sequence.
+var ErrInvalidQuotedBytes = errors.New("ltsvlog: invalid quoted bytes")
+
+// appendBase64 appends value encoded in the standard base64 encoding.
+func appendBase64(buf []byte, value []byte) []byte {
+	start := len(buf)
+	buf = append(buf, make([]byte, base64.StdEncoding.EncodedLen(len(value)))...)
+	base64.StdEncoding.Encode(buf[start:], value)
+	return buf
+}
+
+// DecodeBase64Bytes decodes a value written with Event.Base64Bytes.
+func DecodeBase64Bytes(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// appendQuotedBytes appends value with escaping backslashes, control
+// characters and bytes which are not printable UTF-8 characters.
+// Backslashes are written as "\\", tab, newline and carriage return
+// characters as "\t", "\n" and "\r", and other escaped bytes as "\x"
+// followed by two lower case hex digits.
+func appendQuotedBytes(buf []byte, value []byte) []byte {
+	for i := 0; i < len(value); {
+		b := value[i]
+		if b < utf8.RuneSelf {
+			switch {
+			case b == '\\':
+				buf = append(buf, '\\', '\\')
+			case b == '\t':
+				buf = append(buf, '\\', 't')
+			case b == '\n':
+				buf = append(buf, '\\', 'n')
+			case b == '\r':
+				buf = append(buf, '\\', 'r')
+			case b < 0x20 || b == 0x7f:
+				buf = append(buf, '\\', 'x', digits[b>>4], digits[b&0xF])
+			default:
+				buf = append(buf, b)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(value[i:])
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			for _, b := range value[i : i+size] {
+				buf = append(buf, '\\', 'x', digits[b>>4], digits[b&0xF])
+			}
+		} else {
+			buf = append(buf, value[i:i+size]...)
+		}
+		i += size
+	}
+	return buf
+}
+
+// DecodeQuotedBytes decodes a value written with Event.QuotedBytes.
+// The value must be unescaped with UnescapeValue beforehand, which is
+// done by ParseLine.
+func DecodeQuotedBytes(value string) ([]byte, error) {
+	b := make([]byte, 0, len(value))
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			b = append(b, value[i])
+			continue
+		}
+		if i+1 >= len(value) {
+			return nil, ErrInvalidQuotedBytes
+		}
+		i++
+		switch value[i] {
+		case '\\':
+			b = append(b, '\\')
+		case 't':
+			b = append(b, '\t')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 'x':
+			if i+2 >= len(value) {
+				return nil, ErrInvalidQuotedBytes
+			}
+			hi, ok1 := unhex(value[i+1])
+			lo, ok2 := unhex(value[i+2])
+			if !ok1 || !ok2 {
+				return nil, ErrInvalidQuotedBytes
+			}
+			b = append(b, hi<<4|lo)
+			i += 2
+		default:
+			return nil, ErrInvalidQuotedBytes
+		}
+	}
+	return b, nil
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
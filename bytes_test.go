@@ -0,0 +1,66 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAppendQuotedBytes(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "hello, world", want: "hello, world"},
+		{value: "a\tb\nc\rd\\e", want: `a\tb\nc\rd\\e`},
+		{value: "\x00\x1f\x7f", want: `\x00\x1f\x7f`},
+		{value: "\u3042\xff\xe3\x81", want: "\u3042" + `\xff\xe3\x81`},
+		{value: "\u200b", want: `\xe2\x80\x8b`},
+	}
+	for _, tc := range testCases {
+		got := string(appendQuotedBytes(nil, []byte(tc.value)))
+		if got != tc.want {
+			t.Errorf("appendQuotedBytes(%q) got %q; want %q", tc.value, got, tc.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("appendQuotedBytes(%q) got invalid UTF-8 %q", tc.value, got)
+		}
+		decoded, err := DecodeQuotedBytes(got)
+		if err != nil {
+			t.Errorf("DecodeQuotedBytes(%q) err %v", got, err)
+		} else if string(decoded) != tc.value {
+			t.Errorf("DecodeQuotedBytes(%q) got %q; want %q", got, decoded, tc.value)
+		}
+	}
+}
+
+func TestDecodeQuotedBytes_Invalid(t *testing.T) {
+	for _, value := range []string{`\`, `\a`, `\x`, `\x0`, `\x0g`} {
+		if _, err := DecodeQuotedBytes(value); err != ErrInvalidQuotedBytes {
+			t.Errorf("DecodeQuotedBytes(%q) err got %v; want %v", value, err, ErrInvalidQuotedBytes)
+		}
+	}
+}
+
+func TestEvent_Base64Bytes_QuotedBytes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""))
+	value := []byte("\x00\tfoo\\\xff")
+	logger.Info().Base64Bytes("b64", value).QuotedBytes("quoted", value).Log()
+	want := "level:Info\tb64:AAlmb29c/w==\tquoted:\\\\x00\\\\tfoo\\\\\\\\\\\\xff\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("log unmatch,\n got=%q,\nwant=%q", got, want)
+	}
+
+	fields, err := ParseLine(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DecodeBase64Bytes(fields[1].Value); err != nil || !bytes.Equal(got, value) {
+		t.Errorf("DecodeBase64Bytes got %q, %v; want %q", got, err, value)
+	}
+	if got, err := DecodeQuotedBytes(fields[2].Value); err != nil || !bytes.Equal(got, value) {
+		t.Errorf("DecodeQuotedBytes got %q, %v; want %q", got, err, value)
+	}
+}
@@ -0,0 +1,105 @@
+package ltsvlog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SetCallerLabel returns the option function to set the caller label.
+// If the label is not empty, loggers print the short file path and the
+// line number of the call site of Debug, Info and Err like "pkg/file.go:12".
+// The default label is empty, that is, loggers do not print the caller.
+//
+// The caller values are cached for each program counter, so the cost of
+// looking up the file name and the line number is paid only once for
+// each call site.
+func SetCallerLabel(label string) Option {
+	return func(l *LTSVLogger) {
+		l.callerLabel = label
+	}
+}
+
+// SetCallerSkip returns the option function to set the count of
+// additional stack frames to skip when getting the caller.
+// Use this when you wrap LTSVLogger methods in your own functions.
+func SetCallerSkip(skip int) Option {
+	return func(l *LTSVLogger) {
+		l.callerSkip = skip
+	}
+}
+
+// SetCallerFunc returns the option function to set whether or not
+// loggers print the function name with the caller like
+// "example.com/pkg.Func@pkg/file.go:12", which is similar to the format
+// of stack frames of errors.
+func SetCallerFunc(enabled bool) Option {
+	return func(l *LTSVLogger) {
+		l.callerFunc = enabled
+	}
+}
+
+type callerCache struct {
+	withFunc bool
+	mu       sync.RWMutex
+	m        map[uintptr]string
+}
+
+func newCallerCache(withFunc bool) *callerCache {
+	return &callerCache{
+		withFunc: withFunc,
+		m:        make(map[uintptr]string),
+	}
+}
+
+func (c *callerCache) get(pc uintptr) string {
+	c.mu.RLock()
+	s, ok := c.m[pc]
+	c.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	var b []byte
+	if c.withFunc {
+		b = append(b, frame.Function...)
+		b = append(b, '@')
+	}
+	b = append(b, shortFilePath(frame.File)...)
+	b = append(b, ':')
+	b = strconv.AppendInt(b, int64(frame.Line), 10)
+	s = string(b)
+
+	c.mu.Lock()
+	c.m[pc] = s
+	c.mu.Unlock()
+	return s
+}
+
+// appendCaller appends the caller field.
+// The skip argument is the count of stack frames to skip, with 0
+// identifying the caller of appendCaller.
+func (l *LTSVLogger) appendCaller(buf []byte, skip int) []byte {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2+l.callerSkip, pcs[:]) == 0 {
+		return buf
+	}
+	buf = l.encoder.AppendKey(buf, l.callerLabel)
+	buf = l.encoder.AppendString(buf, l.callerCache.get(pcs[0]))
+	return l.encoder.AppendEndField(buf)
+}
+
+// shortFilePath returns the last directory and the file name of path.
+func shortFilePath(path string) string {
+	i := strings.LastIndexByte(path, '/')
+	if i == -1 {
+		return path
+	}
+	j := strings.LastIndexByte(path[:i], '/')
+	if j == -1 {
+		return path
+	}
+	return path[j+1:]
+}
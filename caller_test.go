@@ -0,0 +1,106 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSetCallerLabel(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	shortFile := filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file)
+
+	buf := new(bytes.Buffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetCallerLabel("caller"))
+	funcLogger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetCallerLabel("caller"), SetCallerFunc(true))
+	wrapperLogger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetCallerLabel("caller"), SetCallerSkip(1))
+	logWrapper := func(msg string) {
+		wrapperLogger.Info().String("msg", msg).Log()
+	}
+
+	testCases := []struct {
+		name string
+		f    func() int
+		want string
+	}{
+		{
+			name: "debug",
+			f: func() int {
+				logger.Debug().String("msg", "hello").Log()
+				return callerLine()
+			},
+			want: "level:Debug\tcaller:%s:%d\tmsg:hello\n",
+		},
+		{
+			name: "info",
+			f: func() int {
+				logger.Info().String("msg", "hello").Log()
+				return callerLine()
+			},
+			want: "level:Info\tcaller:%s:%d\tmsg:hello\n",
+		},
+		{
+			name: "err",
+			f: func() int {
+				logger.Err(errors.New("some error"))
+				return callerLine()
+			},
+			want: "level:Error\tcaller:%s:%d\terr:some error\n",
+		},
+		{
+			name: "func",
+			f: func() int {
+				funcLogger.Info().String("msg", "hello").Log()
+				return callerLine()
+			},
+			want: "level:Info\tcaller:github.com/hnakamur/ltsvlog/v3.TestSetCallerLabel.func5@%s:%d\tmsg:hello\n",
+		},
+		{
+			name: "skip",
+			f: func() int {
+				logWrapper("hello")
+				return callerLine()
+			},
+			want: "level:Info\tcaller:%s:%d\tmsg:hello\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				buf.Reset()
+				line := tc.f() - 1
+				got := buf.String()
+				want := fmt.Sprintf(tc.want, shortFile, line)
+				if got != want {
+					t.Errorf("got %q; want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+// callerLine returns the line number of the caller.
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestShortFilePath(t *testing.T) {
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{path: "/home/user/go/src/example.com/pkg/file.go", want: "pkg/file.go"},
+		{path: "pkg/file.go", want: "pkg/file.go"},
+		{path: "file.go", want: "file.go"},
+	}
+	for _, tc := range testCases {
+		if got := shortFilePath(tc.path); got != tc.want {
+			t.Errorf("path=%s, got %s; want %s", tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package ltsvlog
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorFaint   = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorMagenta = "\x1b[35m"
+)
+
+type consoleEncoder struct {
+	color bool
+}
+
+// NewConsoleEncoder returns the encoder which writes human readable logs
+// for development.
+//
+// The time is printed in the local time zone in the "15:04:05.000" format
+// without the label unless the format is set with SetTimeFormat or
+// SetTimeLayout, and the level is printed in upper case without the
+// label. The value of the message label, which is "msg" by default and
+// can be changed with SetMessageLabel, is moved to just after the level
+// and printed without the label. Other fields are printed as label=value
+// separated by a space. Values which contain spaces, double quotes,
+// equal signs or control characters are quoted.
+//
+// The time, level and labels are colored if the writer of the logger is
+// a terminal and the NO_COLOR environment variable is not set.
+func NewConsoleEncoder() Encoder {
+	return consoleEncoder{}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func (c consoleEncoder) appendPrefixFunc(timeLabel, levelLabel string, tf *timeFormatter, now func() time.Time) appendPrefixFuncType {
+	appendTime := appendConsoleTime
+	if tf != nil {
+		appendTime = tf.appendTime
+	}
+	return func(buf []byte, level string) []byte {
+		if timeLabel != "" {
+			if c.color {
+				buf = append(buf, colorFaint...)
+			}
+			buf = appendTime(buf, now())
+			if c.color {
+				buf = append(buf, colorReset...)
+			}
+			buf = append(buf, ' ')
+		}
+		if levelLabel != "" {
+			buf = c.appendLevel(buf, level)
+			buf = append(buf, ' ')
+		}
+		return buf
+	}
+}
+
+func (c consoleEncoder) appendLevel(buf []byte, level string) []byte {
+	var color, text string
+	switch level {
+	case "Debug":
+		color, text = colorMagenta, "DEBUG"
+	case "Info":
+		color, text = colorGreen, "INFO "
+	case "Error":
+		color, text = colorRed, "ERROR"
+	default:
+		text = level
+	}
+	if c.color && color != "" {
+		buf = append(buf, color...)
+		buf = append(buf, text...)
+		return append(buf, colorReset...)
+	}
+	return append(buf, text...)
+}
+
+func appendConsoleTime(buf []byte, t time.Time) []byte {
+	t = t.Local()
+	tmp := []byte("00:00:00.000")
+	hour, min, sec := t.Clock()
+	itoa(tmp[:2], hour, 2)
+	itoa(tmp[3:5], min, 2)
+	itoa(tmp[6:8], sec, 2)
+	itoa(tmp[9:12], t.Nanosecond()/1e6, 3)
+	return append(buf, tmp...)
+}
+
+func (consoleEncoder) AppendBeginRecord(buf []byte) []byte {
+	return buf
+}
+
+func (consoleEncoder) AppendEndRecord(buf []byte) []byte {
+	if len(buf) > 0 && buf[len(buf)-1] == ' ' {
+		buf[len(buf)-1] = '\n'
+		return buf
+	}
+	return append(buf, '\n')
+}
+
+func (c consoleEncoder) AppendKey(buf []byte, label string) []byte {
+	if c.color {
+		buf = append(buf, colorFaint...)
+		buf = append(buf, label...)
+		buf = append(buf, '=')
+		return append(buf, colorReset...)
+	}
+	buf = append(buf, label...)
+	return append(buf, '=')
+}
+
+func (c consoleEncoder) AppendPrefixedKey(buf []byte, prefix []byte, label string) []byte {
+	if c.color {
+		buf = append(buf, colorFaint...)
+	}
+	buf = append(buf, prefix...)
+	buf = append(buf, label...)
+	buf = append(buf, '=')
+	if c.color {
+		buf = append(buf, colorReset...)
+	}
+	return buf
+}
+
+func (consoleEncoder) AppendEndField(buf []byte) []byte {
+	return append(buf, ' ')
+}
+
+func (c consoleEncoder) AppendString(buf []byte, value string) []byte {
+	start := len(buf)
+	buf = append(buf, value...)
+	return c.EncodeStringAt(buf, start)
+}
+
+// EncodeStringAt quotes the value if needed.
+// The message value is not preceded by a label, so the buf ends with
+// the field separator or is empty. It is never quoted and only newline,
+// tab and backslash characters are escaped.
+func (consoleEncoder) EncodeStringAt(buf []byte, start int) []byte {
+	if start == 0 || buf[start-1] == ' ' {
+		return ltsvEscapeAt(buf, start)
+	}
+	if start == len(buf) || needsQuote(buf[start:]) {
+		return jsonEncodeAt(buf, start)
+	}
+	return buf
+}
+
+func (consoleEncoder) AppendBool(buf []byte, value bool) []byte {
+	return strconv.AppendBool(buf, value)
+}
+
+func (consoleEncoder) AppendInt64(buf []byte, value int64) []byte {
+	return strconv.AppendInt(buf, value, 10)
+}
+
+func (consoleEncoder) AppendUint64(buf []byte, value uint64) []byte {
+	return strconv.AppendUint(buf, value, 10)
+}
+
+func (consoleEncoder) AppendFloat(buf []byte, value float64, bitSize int) []byte {
+	return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+}
+
+func (consoleEncoder) AppendHexByte(buf []byte, value byte) []byte {
+	return appendHexByte(buf, value)
+}
+
+func (consoleEncoder) AppendHexBytes(buf []byte, value []byte) []byte {
+	return appendHexBytes(buf, value)
+}
+
+func (consoleEncoder) AppendUTCTime(buf []byte, value time.Time) []byte {
+	return appendUTCTime(buf, value)
+}
+
+func (consoleEncoder) AppendBeginArray(buf []byte) []byte {
+	return buf
+}
+
+func (consoleEncoder) AppendArraySeparator(buf []byte) []byte {
+	return append(buf, ',')
+}
+
+func (consoleEncoder) AppendArrayString(buf []byte, value string) []byte {
+	return appendListString(buf, value)
+}
+
+func (c consoleEncoder) AppendEndArray(buf []byte, start, n int) []byte {
+	return c.EncodeStringAt(appendEndList(buf, n), start)
+}
@@ -0,0 +1,96 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestConsoleEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetEncoder(NewConsoleEncoder()))
+
+	testCases := []struct {
+		name string
+		f    func(l *LTSVLogger)
+		want string
+	}{
+		{
+			name: "msg_only",
+			f: func(l *LTSVLogger) {
+				l.Debug().String("msg", "hello world").Log()
+			},
+			want: "DEBUG hello world\n",
+		},
+		{
+			name: "msg_first",
+			f: func(l *LTSVLogger) {
+				l.Info().String("reqID", "req1").Int("n", 2).String("msg", "hello").Bool("ok", true).Log()
+			},
+			want: "INFO  hello reqID=req1 n=2 ok=true\n",
+		},
+		{
+			name: "msg_twice",
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "first").String("msg", "second").Log()
+			},
+			want: "INFO  first msg=second\n",
+		},
+		{
+			name: "quote",
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "a\tb").String("a", "b c").String("d", `e"f`).
+					String("g", "h=i").String("empty", "").String("j", "k\nl").Log()
+			},
+			want: "INFO  a\\tb a=\"b c\" d=\"e\\\"f\" g=\"h=i\" empty=\"\" j=\"k\\nl\"\n",
+		},
+		{
+			name: "time_value",
+			f: func(l *LTSVLogger) {
+				t := time.Date(2017, 5, 21, 12, 44, 56, 987654321, time.UTC)
+				l.Info().UTCTime("t", t).Log()
+			},
+			want: "INFO  t=2017-05-21T12:44:56.987654Z\n",
+		},
+		{
+			name: "err",
+			f: func(l *LTSVLogger) {
+				l.Err(errstack.WithLV(errors.New("some error")).String("reqID", "req1"))
+			},
+			want: "ERROR err=\"some error\" reqID=req1\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.f(logger)
+			got := buf.String()
+			if got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConsoleEncoder_Color(t *testing.T) {
+	buf := new(bytes.Buffer)
+	enc := consoleEncoder{color: true}
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetEncoder(enc))
+
+	logger.Info().String("a", "b").String("msg", "hello").Log()
+	want := "\x1b[32mINFO \x1b[0m hello \x1b[2ma=\x1b[0mb\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestAppendConsoleTime(t *testing.T) {
+	got := string(appendConsoleTime(nil, time.Date(2017, 5, 7, 2, 3, 4, 5678901, time.Local)))
+	if want := "02:03:04.005"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
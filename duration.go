@@ -0,0 +1,142 @@
+package ltsvlog
+
+import "time"
+
+// DurationFormat is the format of values written with Event.Duration.
+type DurationFormat int
+
+const (
+	// DurationFormatString is the default format, which is the same as
+	// time.Duration.String like "1.5s".
+	DurationFormatString DurationFormat = iota
+	// DurationFormatSeconds is the number of seconds as a float like 1.5.
+	DurationFormatSeconds
+	// DurationFormatMillis is the number of milliseconds as an integer
+	// like 1500. The fraction is truncated.
+	DurationFormatMillis
+)
+
+// SetDurationFormat returns the option function to set the format of
+// values written with Event.Duration.
+func SetDurationFormat(format DurationFormat) Option {
+	return func(l *LTSVLogger) {
+		l.durationFormat = format
+	}
+}
+
+// appendDuration appends d in the same format as time.Duration.String
+// without allocation.
+//
+// appendDuration, fmtFrac and fmtInt are copied from
+// https://github.com/golang/go/blob/go1.18/src/time/time.go
+// and modified for ltsvlog.
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license which can be
+// found at https://github.com/golang/go/blob/go1.18/LICENSE
+func appendDuration(buf []byte, d time.Duration) []byte {
+	// Largest time is 2540400h10m10.000000000s
+	var arr [32]byte
+	w := len(arr)
+
+	u := uint64(d)
+	neg := d < 0
+	if neg {
+		u = -u
+	}
+
+	if u < uint64(time.Second) {
+		// Special case: if duration is smaller than a second,
+		// use smaller units, like 1.2ms
+		var prec int
+		w--
+		arr[w] = 's'
+		w--
+		switch {
+		case u == 0:
+			arr[w] = '0'
+			return append(buf, arr[w:]...)
+		case u < uint64(time.Microsecond):
+			prec = 0
+			arr[w] = 'n'
+		case u < uint64(time.Millisecond):
+			prec = 3
+			// U+00B5 micro sign == 0xC2 0xB5
+			w--
+			copy(arr[w:], "\xc2\xb5")
+		default:
+			prec = 6
+			arr[w] = 'm'
+		}
+		w, u = fmtFrac(arr[:w], u, prec)
+		w = fmtInt(arr[:w], u)
+	} else {
+		w--
+		arr[w] = 's'
+		w, u = fmtFrac(arr[:w], u, 9)
+
+		// u is now integer seconds
+		w = fmtInt(arr[:w], u%60)
+		u /= 60
+
+		// u is now integer minutes
+		if u > 0 {
+			w--
+			arr[w] = 'm'
+			w = fmtInt(arr[:w], u%60)
+			u /= 60
+
+			// u is now integer hours
+			if u > 0 {
+				w--
+				arr[w] = 'h'
+				w = fmtInt(arr[:w], u)
+			}
+		}
+	}
+
+	if neg {
+		w--
+		arr[w] = '-'
+	}
+	return append(buf, arr[w:]...)
+}
+
+// fmtFrac formats the fraction of v/10**prec (e.g., ".12345") into the
+// tail of buf, omitting trailing zeros. It omits the decimal
+// point too when the fraction is 0. It returns the index where the
+// output bytes begin and the value v/10**prec.
+func fmtFrac(buf []byte, v uint64, prec int) (nw int, nv uint64) {
+	w := len(buf)
+	print := false
+	for i := 0; i < prec; i++ {
+		digit := v % 10
+		print = print || digit != 0
+		if print {
+			w--
+			buf[w] = byte(digit) + '0'
+		}
+		v /= 10
+	}
+	if print {
+		w--
+		buf[w] = '.'
+	}
+	return w, v
+}
+
+// fmtInt formats v into the tail of buf.
+// It returns the index where the output begins.
+func fmtInt(buf []byte, v uint64) int {
+	w := len(buf)
+	if v == 0 {
+		w--
+		buf[w] = '0'
+	} else {
+		for v > 0 {
+			w--
+			buf[w] = byte(v%10) + '0'
+			v /= 10
+		}
+	}
+	return w
+}
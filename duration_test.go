@@ -0,0 +1,52 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAppendDuration(t *testing.T) {
+	testCases := []time.Duration{
+		0,
+		1,
+		999,
+		1100 * time.Nanosecond,
+		2200 * time.Microsecond,
+		3300 * time.Millisecond,
+		4*time.Minute + 5*time.Second,
+		4*time.Minute + 5001*time.Millisecond,
+		5*time.Hour + 6*time.Minute + 7001*time.Millisecond,
+		-1500 * time.Microsecond,
+		math.MaxInt64,
+		math.MinInt64,
+	}
+	for _, d := range testCases {
+		if got, want := string(appendDuration(nil, d)), d.String(); got != want {
+			t.Errorf("appendDuration(%d) got %q; want %q", int64(d), got, want)
+		}
+	}
+}
+
+func TestEvent_Duration(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format DurationFormat
+		want   string
+	}{
+		{name: "string", format: DurationFormatString, want: "level:Info\telapsed:1.5s\n"},
+		{name: "seconds", format: DurationFormatSeconds, want: "level:Info\telapsed:1.5\n"},
+		{name: "millis", format: DurationFormatMillis, want: "level:Info\telapsed:1500\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLTSVLogger(&buf, false, SetTimeLabel(""), SetDurationFormat(tc.format))
+			logger.Info().Duration("elapsed", 1500*time.Millisecond).Log()
+			if got := buf.String(); got != tc.want {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package ltsvlog
+
+import (
+	"strconv"
+	"time"
+)
+
+// Encoder is the interface for encoding log records.
+//
+// A record is built by calling AppendBeginRecord, then for each field
+// AppendKey, one of the value methods and AppendEndField, and finally
+// AppendEndRecord which must terminate the record with a newline.
+// AppendPrefixedKey is used instead of AppendKey for fields in a namespace
+// started with Event.Namespace, and it must append the key which is the
+// same as the one AppendKey appends for the concatenation of prefix and
+// label.
+// Value methods must not append field separators, those are appended by
+// AppendEndField.
+//
+// EncodeStringAt encodes the raw string value which has been appended
+// at buf[start:] in place. It is used for values which are formatted
+// directly into buf, like time values.
+//
+// A list value is built by calling AppendBeginArray, then for each
+// element AppendArraySeparator except for the first element and one of
+// AppendArrayString, AppendInt64, AppendUint64 and AppendFloat, and
+// finally AppendEndArray with the start position of the value before
+// AppendBeginArray and the count of elements.
+type Encoder interface {
+	AppendBeginRecord(buf []byte) []byte
+	AppendEndRecord(buf []byte) []byte
+	AppendKey(buf []byte, label string) []byte
+	AppendPrefixedKey(buf []byte, prefix []byte, label string) []byte
+	AppendEndField(buf []byte) []byte
+
+	AppendString(buf []byte, value string) []byte
+	EncodeStringAt(buf []byte, start int) []byte
+	AppendBool(buf []byte, value bool) []byte
+	AppendInt64(buf []byte, value int64) []byte
+	AppendUint64(buf []byte, value uint64) []byte
+	AppendFloat(buf []byte, value float64, bitSize int) []byte
+	AppendHexByte(buf []byte, value byte) []byte
+	AppendHexBytes(buf []byte, value []byte) []byte
+	AppendUTCTime(buf []byte, value time.Time) []byte
+
+	AppendBeginArray(buf []byte) []byte
+	AppendArraySeparator(buf []byte) []byte
+	AppendArrayString(buf []byte, value string) []byte
+	AppendEndArray(buf []byte, start, n int) []byte
+}
+
+type ltsvEncoder struct{}
+
+// NewLTSVEncoder returns the encoder which writes logs in LTSV format.
+// This is the default encoder of LTSVLogger.
+func NewLTSVEncoder() Encoder {
+	return ltsvEncoder{}
+}
+
+func (ltsvEncoder) AppendBeginRecord(buf []byte) []byte {
+	return buf
+}
+
+func (ltsvEncoder) AppendEndRecord(buf []byte) []byte {
+	if len(buf) > 0 && buf[len(buf)-1] == '\t' {
+		buf[len(buf)-1] = '\n'
+		return buf
+	}
+	return append(buf, '\n')
+}
+
+func (ltsvEncoder) AppendKey(buf []byte, label string) []byte {
+	buf = append(buf, label...)
+	return append(buf, ':')
+}
+
+func (ltsvEncoder) AppendPrefixedKey(buf []byte, prefix []byte, label string) []byte {
+	buf = append(buf, prefix...)
+	buf = append(buf, label...)
+	return append(buf, ':')
+}
+
+func (ltsvEncoder) AppendEndField(buf []byte) []byte {
+	return append(buf, '\t')
+}
+
+func (ltsvEncoder) AppendString(buf []byte, value string) []byte {
+	return appendEscaped(buf, value)
+}
+
+func (ltsvEncoder) EncodeStringAt(buf []byte, start int) []byte {
+	return ltsvEscapeAt(buf, start)
+}
+
+// ltsvEscapeAt escapes tab, newline and backslash characters in buf[start:]
+// in place.
+func ltsvEscapeAt(buf []byte, start int) []byte {
+	n := 0
+	for _, b := range buf[start:] {
+		if b == '\t' || b == '\n' || b == '\\' {
+			n++
+		}
+	}
+	if n == 0 {
+		return buf
+	}
+
+	end := len(buf)
+	buf = append(buf, make([]byte, n)...)
+	j := len(buf) - 1
+	for i := end - 1; i >= start; i-- {
+		switch b := buf[i]; b {
+		case '\t':
+			buf[j-1], buf[j] = '\\', 't'
+			j -= 2
+		case '\n':
+			buf[j-1], buf[j] = '\\', 'n'
+			j -= 2
+		case '\\':
+			buf[j-1], buf[j] = '\\', '\\'
+			j -= 2
+		default:
+			buf[j] = b
+			j--
+		}
+	}
+	return buf
+}
+
+func (ltsvEncoder) AppendBool(buf []byte, value bool) []byte {
+	return strconv.AppendBool(buf, value)
+}
+
+func (ltsvEncoder) AppendInt64(buf []byte, value int64) []byte {
+	return strconv.AppendInt(buf, value, 10)
+}
+
+func (ltsvEncoder) AppendUint64(buf []byte, value uint64) []byte {
+	return strconv.AppendUint(buf, value, 10)
+}
+
+func (ltsvEncoder) AppendFloat(buf []byte, value float64, bitSize int) []byte {
+	return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+}
+
+func (ltsvEncoder) AppendHexByte(buf []byte, value byte) []byte {
+	return appendHexByte(buf, value)
+}
+
+func (ltsvEncoder) AppendHexBytes(buf []byte, value []byte) []byte {
+	return appendHexBytes(buf, value)
+}
+
+func (ltsvEncoder) AppendUTCTime(buf []byte, value time.Time) []byte {
+	return appendUTCTime(buf, value)
+}
+
+func (ltsvEncoder) AppendBeginArray(buf []byte) []byte {
+	return buf
+}
+
+func (ltsvEncoder) AppendArraySeparator(buf []byte) []byte {
+	return append(buf, ',')
+}
+
+func (ltsvEncoder) AppendArrayString(buf []byte, value string) []byte {
+	return appendListString(buf, value)
+}
+
+func (ltsvEncoder) AppendEndArray(buf []byte, start, n int) []byte {
+	return ltsvEscapeAt(appendEndList(buf, n), start)
+}
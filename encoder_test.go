@@ -0,0 +1,31 @@
+package ltsvlog
+
+import "testing"
+
+func TestEncodeStringAt(t *testing.T) {
+	testCases := []struct {
+		name string
+		enc  Encoder
+		buf  string
+		val  string
+		want string
+	}{
+		{name: "ltsv_plain", enc: NewLTSVEncoder(), buf: "a:", val: "b c", want: "a:b c"},
+		{name: "ltsv_escape", enc: NewLTSVEncoder(), buf: "a:", val: "b\tc\nd\\e", want: `a:b\tc\nd\\e`},
+		{name: "json_plain", enc: NewJSONEncoder(), buf: `"a":`, val: "b c", want: `"a":"b c"`},
+		{name: "json_escape", enc: NewJSONEncoder(), buf: `"a":`, val: "b\tc\"d\xff", want: `"a":"b\tc\"d\ufffd"`},
+		{name: "logfmt_plain", enc: NewLogfmtEncoder(), buf: "a=", val: "b", want: "a=b"},
+		{name: "logfmt_quote", enc: NewLogfmtEncoder(), buf: "a=", val: "b c", want: `a="b c"`},
+		{name: "console_msg", enc: NewConsoleEncoder(), buf: "INFO  ", val: "b c\n", want: `INFO  b c\n`},
+		{name: "console_quote", enc: NewConsoleEncoder(), buf: "a=", val: "b c", want: `a="b c"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := append([]byte(tc.buf), tc.val...)
+			got := string(tc.enc.EncodeStringAt(buf, len(tc.buf)))
+			if got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package ltsvlog
+
+import (
+	"strconv"
+
+	"github.com/hnakamur/errstack"
+)
+
+// LabeledValuer is the interface implemented by errors which have
+// pairs of labels and values to be logged with Err.
+// LV must return labels and values alternately.
+//
+// The errors created with github.com/hnakamur/errstack.WithLV implement
+// this interface.
+type LabeledValuer interface {
+	LV() []string
+}
+
+// SetErrCauses returns the option function to set whether or not
+// Err writes the messages of the errors wrapped in err.
+//
+// The wrapped errors are walked in depth first order with the
+// Unwrap() error and Unwrap() []error methods, and each message is
+// written with the label which is the err label followed by "_cause"
+// and the sequence number starting at 1, like "err_cause1".
+// A message which is the same as the one of the wrapping error is
+// skipped, since errors like the ones in github.com/hnakamur/errstack
+// only add values to the wrapped error.
+func SetErrCauses(enabled bool) Option {
+	return func(l *LTSVLogger) {
+		l.errCauses = enabled
+	}
+}
+
+// appendErrCauses appends the messages of the errors wrapped in err
+// and returns the updated sequence number n.
+func (e *Event) appendErrCauses(label string, err error, n int) int {
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if u := x.Unwrap(); u != nil {
+			n = e.appendErrCause(label, err, u, n)
+		}
+	case interface{ Unwrap() []error }:
+		for _, u := range x.Unwrap() {
+			if u != nil {
+				n = e.appendErrCause(label, err, u, n)
+			}
+		}
+	}
+	return n
+}
+
+func (e *Event) appendErrCause(label string, parent, err error, n int) int {
+	if msg := err.Error(); msg != parent.Error() {
+		n++
+		e.String(label+"_cause"+strconv.Itoa(n), msg)
+	}
+	return e.appendErrCauses(label, err, n)
+}
+
+// appendErrLV appends the labeled values of err and the errors wrapped
+// in err. outer is the labeled values of the nearest error which wraps
+// err with the Unwrap() error method and implements LabeledValuer.
+// The labeled values of err are skipped if outer starts with them, since
+// github.com/hnakamur/errstack.WithLV includes the labeled values of the
+// wrapped error.
+func (e *Event) appendErrLV(err error, outer []string) {
+	if v, ok := err.(LabeledValuer); ok {
+		lv := v.LV()
+		if !hasLVPrefix(outer, lv) {
+			for i := 0; i+1 < len(lv); i += 2 {
+				e.String(lv[i], lv[i+1])
+			}
+		}
+		outer = lv
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if u := x.Unwrap(); u != nil {
+			e.appendErrLV(u, outer)
+		}
+	case interface{ Unwrap() []error }:
+		for _, u := range x.Unwrap() {
+			if u != nil {
+				e.appendErrLV(u, nil)
+			}
+		}
+	}
+}
+
+// hasLVPrefix returns whether or not lv starts with prefix.
+func hasLVPrefix(lv, prefix []string) bool {
+	if len(prefix) > len(lv) {
+		return false
+	}
+	for i, v := range prefix {
+		if lv[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// errStack returns the first stack found in err and the errors wrapped
+// in err in depth first order. Unlike github.com/hnakamur/errstack.Stack,
+// the errors joined with the Unwrap() []error method are also walked.
+func errStack(err error) []errstack.Frame {
+	switch x := err.(type) {
+	case interface{ Stack() []errstack.Frame }:
+		return x.Stack()
+	case interface{ Unwrap() error }:
+		if u := x.Unwrap(); u != nil {
+			return errStack(u)
+		}
+	case interface{ Unwrap() []error }:
+		for _, u := range x.Unwrap() {
+			if u == nil {
+				continue
+			}
+			if ff := errStack(u); len(ff) > 0 {
+				return ff
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hnakamur/errstack"
+)
+
+type testLVError struct {
+	err error
+	lv  []string
+}
+
+func (e *testLVError) Error() string { return e.err.Error() }
+func (e *testLVError) Unwrap() error { return e.err }
+func (e *testLVError) LV() []string  { return e.lv }
+
+type testJoinError []error
+
+func (e testJoinError) Error() string {
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e testJoinError) Unwrap() []error { return e }
+
+func TestEvent_Err_Chain(t *testing.T) {
+	testCases := []struct {
+		name   string
+		causes bool
+		err    error
+		want   string
+	}{
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("read config: %w", errstack.WithLV(errors.New("no such file")).String("path", "/etc/app.conf")),
+			want: "level:Error\terr:read config: no such file\tpath:/etc/app.conf\n",
+		},
+		{
+			name: "custom",
+			err:  fmt.Errorf("query: %w", &testLVError{err: errors.New("timeout"), lv: []string{"table", "users"}}),
+			want: "level:Error\terr:query: timeout\ttable:users\n",
+		},
+		{
+			name: "nestedCustom",
+			err: &testLVError{
+				err: &testLVError{err: errors.New("x"), lv: []string{"inner", "2"}},
+				lv:  []string{"outer", "1"},
+			},
+			want: "level:Error\terr:x\touter:1\tinner:2\n",
+		},
+		{
+			name: "nestedErrstack",
+			err: errstack.WithLV(fmt.Errorf("wrap: %w",
+				errstack.WithLV(errors.New("x")).String("inner", "2"))).String("outer", "1"),
+			want: "level:Error\terr:wrap: x\tinner:2\touter:1\n",
+		},
+		{
+			name: "errstackInCustom",
+			err: &testLVError{
+				err: errstack.WithLV(errors.New("x")).String("inner", "2"),
+				lv:  []string{"outer", "1"},
+			},
+			want: "level:Error\terr:x\touter:1\tinner:2\n",
+		},
+		{
+			name: "joined",
+			err: testJoinError{
+				&testLVError{err: errors.New("err1"), lv: []string{"id", "1"}},
+				fmt.Errorf("wrap: %w", &testLVError{err: errors.New("err2"), lv: []string{"id", "2"}}),
+			},
+			want: "level:Error\terr:err1; wrap: err2\tid:1\tid:2\n",
+		},
+		{
+			name:   "causes",
+			causes: true,
+			err:    fmt.Errorf("read config: %w", errstack.WithLV(fmt.Errorf("open: %w", errors.New("no such file"))).String("path", "/etc/app.conf")),
+			want:   "level:Error\terr:read config: open: no such file\terr_cause1:open: no such file\terr_cause2:no such file\tpath:/etc/app.conf\n",
+		},
+		{
+			name:   "joinedCauses",
+			causes: true,
+			err:    testJoinError{errors.New("err1"), errors.New("err2")},
+			want:   "level:Error\terr:err1; err2\terr_cause1:err1\terr_cause2:err2\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLTSVLogger(&buf, false, SetTimeLabel(""), SetErrCauses(tc.causes))
+			logger.Err(tc.err)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvent_Err_WrappedStack(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLTSVLogger(&buf, false, SetTimeLabel(""))
+	err := testJoinError{errors.New("err1"), fmt.Errorf("wrap: %w", errstack.New("err2"))}
+	logger.Err(err)
+	if got, want := buf.String(), "\tstack:github.com/hnakamur/ltsvlog/v3.TestEvent_Err_WrappedStack@"; !strings.Contains(got, want) {
+		t.Errorf("log got %q; want to contain %q", got, want)
+	}
+}
@@ -0,0 +1,183 @@
+package ltsvlog
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SetErrDedup returns the option function to enable suppressing
+// duplicate error logs written with Err.
+//
+// Errors are regarded as duplicates if err.Error() and the top frame of
+// the call stack of err are the same. The first error
+// is written, and duplicates within the window after that are suppressed.
+// After the window has passed, a summary log with the err value and the
+// count of suppressed errors with the "repeated" label is written by a
+// timer, or at the next call of Err if it comes first. If the clock set
+// with SetClock does not advance, the timer writes the summary after the
+// window passes on the wall clock.
+func SetErrDedup(window time.Duration) Option {
+	return func(l *LTSVLogger) {
+		l.errDedup = &errDeduper{
+			window:  window,
+			entries: make(map[string]*errDedupEntry),
+		}
+	}
+}
+
+type errDeduper struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*errDedupEntry
+	nextSweep time.Time
+	// timerSet is whether or not the timer to flush summaries is set.
+	timerSet bool
+}
+
+type errDedupEntry struct {
+	errMsg   string
+	until    time.Time
+	repeated int
+}
+
+// check returns whether or not err must be written, and the entries of
+// expired windows which have suppressed errors. If setTimer is true,
+// the caller must set the timer to call flush after the window.
+func (d *errDeduper) check(now time.Time, err error) (write bool, expired []errDedupEntry, setTimer bool) {
+	errMsg := err.Error()
+	key := errMsg
+	if ff := errStack(err); len(ff) > 0 {
+		key += "\x00" + ff[0].String()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !now.Before(d.nextSweep) {
+		expired = d.sweep(now)
+	}
+
+	if e, ok := d.entries[key]; ok {
+		if now.Before(e.until) {
+			e.repeated++
+			if !d.timerSet {
+				d.timerSet = true
+				setTimer = true
+			}
+			return false, expired, setTimer
+		}
+		if e.repeated > 0 {
+			expired = append(expired, *e)
+		}
+	}
+	d.entries[key] = &errDedupEntry{errMsg: errMsg, until: now.Add(d.window)}
+	return true, expired, false
+}
+
+// flush returns the entries of expired windows which have suppressed
+// errors. If wait is positive, the caller must set the timer to call
+// flush again after wait, since there are suppressed errors whose
+// windows have not expired yet.
+func (d *errDeduper) flush(now time.Time) (expired []errDedupEntry, wait time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expired = d.sweep(now)
+	d.timerSet = false
+	for _, e := range d.entries {
+		if e.repeated > 0 && (!d.timerSet || e.until.Sub(now) < wait) {
+			d.timerSet = true
+			wait = e.until.Sub(now)
+		}
+	}
+	return expired, wait
+}
+
+// flushAll returns all entries which have suppressed errors, and resets
+// their counts. The entries are kept, so duplicates are still suppressed
+// until their windows expire.
+func (d *errDeduper) flushAll() []errDedupEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var pending []errDedupEntry
+	for _, e := range d.entries {
+		if e.repeated > 0 {
+			pending = append(pending, *e)
+			e.repeated = 0
+		}
+	}
+	d.timerSet = false
+	sortErrDedupEntries(pending)
+	return pending
+}
+
+// sweep deletes the entries of expired windows, and returns the ones
+// which have suppressed errors sorted by the end of windows.
+// d.mu must be held.
+func (d *errDeduper) sweep(now time.Time) []errDedupEntry {
+	var expired []errDedupEntry
+	for k, e := range d.entries {
+		if !now.Before(e.until) {
+			if e.repeated > 0 {
+				expired = append(expired, *e)
+			}
+			delete(d.entries, k)
+		}
+	}
+	d.nextSweep = now.Add(d.window)
+	sortErrDedupEntries(expired)
+	return expired
+}
+
+// sortErrDedupEntries sorts entries by the end of windows.
+func sortErrDedupEntries(entries []errDedupEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].until.Equal(entries[j].until) {
+			return entries[i].until.Before(entries[j].until)
+		}
+		return entries[i].errMsg < entries[j].errMsg
+	})
+}
+
+// setErrDedupTimer sets the timer to write the summaries of suppressed
+// errors after wait.
+//
+// The timer runs on the wall clock. If the clock set with SetClock has
+// not advanced when the timer fires, the summaries of all suppressed
+// errors are written, since their windows would never expire otherwise.
+func (l *LTSVLogger) setErrDedupTimer(wait time.Duration) {
+	setAt := l.now()
+	time.AfterFunc(wait, func() {
+		now := l.now()
+		var expired []errDedupEntry
+		var wait time.Duration
+		if now.After(setAt) {
+			expired, wait = l.errDedup.flush(now)
+		} else {
+			expired = l.errDedup.flushAll()
+		}
+		for _, e := range expired {
+			l.writeErrRepeated(e)
+		}
+		if wait > 0 {
+			l.setErrDedupTimer(wait)
+		}
+	})
+}
+
+// writeErrRepeated writes the summary of suppressed errors. The err value
+// is appended as a string with Event.String, so it is redacted the same
+// way as the err value written by Err.
+func (l *LTSVLogger) writeErrRepeated(e errDedupEntry) {
+	ev := eventPool.Get().(*Event)
+	ev.logger = l
+	ev.enabled = true
+	ev.level = "Error"
+	ev.buf = ev.buf[:0]
+	ev.buf = l.appendPrefixFunc(ev.buf, "Error")
+	ev.resetFields()
+	ev.String("err", e.errMsg).Int("repeated", e.repeated).Log()
+}
@@ -0,0 +1,143 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestSetErrDedup(t *testing.T) {
+	now := time.Date(2017, 5, 7, 22, 13, 59, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	buf := new(bytes.Buffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetClock(clock), SetErrDedup(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		logger.Err(errors.New("error1"))
+		logger.Err(errors.New("error2"))
+	}
+	logger.Err(errors.New("error2"))
+	want := "level:Error\terr:error1\n" +
+		"level:Error\terr:error2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	buf.Reset()
+	now = now.Add(time.Minute)
+	logger.Err(errors.New("error1"))
+	want = "level:Error\terr:error1\trepeated:2\n" +
+		"level:Error\terr:error2\trepeated:3\n" +
+		"level:Error\terr:error1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	buf.Reset()
+	now = now.Add(time.Minute)
+	logger.Err(errors.New("error1"))
+	want = "level:Error\terr:error1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestSetErrDedup_Stack(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetErrDedup(time.Minute))
+
+	newErr1 := func() error { return errstack.New("error") }
+	newErr2 := func() error { return errstack.New("error") }
+	logger.Err(newErr1())
+	logger.Err(newErr1())
+	logger.Err(newErr2())
+	if got, want := bytes.Count(buf.Bytes(), []byte("\n")), 2; got != want {
+		t.Errorf("line count mismatch, got %d; want %d", got, want)
+	}
+}
+
+func TestSetErrDedup_Nil(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetErrDedup(time.Minute))
+	logger.Err(nil)
+	if got, want := buf.String(), ""; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSetErrDedup_Timer(t *testing.T) {
+	buf := new(syncBuffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetErrDedup(20*time.Millisecond))
+
+	// A burst of errors, and then silence.
+	for i := 0; i < 3; i++ {
+		logger.Err(errors.New("error1"))
+	}
+	want := "level:Error\terr:error1\n" +
+		"level:Error\terr:error1\trepeated:2\n"
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() != want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestSetErrDedup_FixedClock(t *testing.T) {
+	now := time.Date(2017, 5, 7, 22, 13, 59, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	buf := new(syncBuffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetClock(clock),
+		SetErrDedup(20*time.Millisecond), SetRedactLabels("err"))
+	for i := 0; i < 3; i++ {
+		logger.Err(errors.New("password=hunter2"))
+	}
+	want := "level:Error\terr:[REDACTED]\n" +
+		"level:Error\terr:[REDACTED]\trepeated:2\n"
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() != want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	// The window has not expired with the fixed clock, so duplicates are
+	// still suppressed.
+	buf.Reset()
+	logger.Err(errors.New("password=hunter2"))
+	if got := buf.String(); got != "" {
+		t.Errorf("got %q; want %q", got, "")
+	}
+}
@@ -2,11 +2,19 @@ package ltsvlog
 
 import (
 	"fmt"
-	"strconv"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
 	"sync"
 	"time"
 )
 
+// maxPooledBufSize is the maximum capacity of the buffer of an Event
+// which is put back to the event pool, so that one huge log does not pin
+// the memory forever.
+const maxPooledBufSize = 64 * 1024
+
 var eventPool = &sync.Pool{
 	New: func() interface{} {
 		return &Event{
@@ -15,12 +23,40 @@ var eventPool = &sync.Pool{
 	},
 }
 
-// Event is a temporary object for building a log record of
-// Debug or Info level.
+// disabledEvent is the Event shared by all disabled loggers.
+// It is never put into the event pool and must not be modified, which
+// holds since all Event methods return immediately when the Event is
+// disabled.
+var disabledEvent = &Event{}
+
+// Event is a temporary object for building a log record.
 type Event struct {
 	logger  *LTSVLogger
 	enabled bool
+	level   string
 	buf     []byte
+
+	// fieldsStart is the position in buf just after the time and level.
+	fieldsStart int
+	// msgStart and msgEnd are the range of the message field in buf.
+	// They are tracked only when the logger puts the message first
+	// or samples logs.
+	msgStart int
+	msgEnd   int
+	// redactStart is the start position of the value to be redacted,
+	// or -1 if the value of the current field is not redacted.
+	redactStart int
+	// prefix is the prefix of labels in the current namespace, and
+	// prefixEnds are the lengths of prefix of the outer namespaces.
+	prefix     []byte
+	prefixEnds []int
+}
+
+// Enabled returns whether or not this Event is enabled, that is,
+// appended values will be written when Log is called.
+// Note an enabled Event may still be dropped by the sampler in Log.
+func (e *Event) Enabled() bool {
+	return e.enabled
 }
 
 // String appends a labeled string value to Event.
@@ -28,23 +64,22 @@ func (e *Event) String(label string, value string) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = append(e.buf, escape(value)...)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendString(e.buf, value)
+	e.endField()
 	return e
 }
 
 // Stringer appends a labeled string value to Event.
 // The value will be converted to a string with String() method.
+// Note String() may allocate memory.
 func (e *Event) Stringer(label string, value fmt.Stringer) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = append(e.buf, escape(value.String())...)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendString(e.buf, value.String())
+	e.endField()
 	return e
 }
 
@@ -53,10 +88,9 @@ func (e *Event) HexBytes(label string, value []byte) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = appendHexBytes(e.buf, value)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendHexBytes(e.buf, value)
+	e.endField()
 	return e
 }
 
@@ -66,15 +100,47 @@ func (e *Event) Bytes(label string, value []byte) *Event {
 	return e.HexBytes(label, value)
 }
 
+// Base64Bytes appends a labeled bytes value in the standard base64
+// encoding with padding to Event. Use DecodeBase64Bytes to decode it.
+func (e *Event) Base64Bytes(label string, value []byte) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = appendBase64(e.buf, value)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
+// QuotedBytes appends a labeled bytes value to Event as a string.
+// Printable UTF-8 characters are written as they are, and backslashes,
+// control characters and other bytes are escaped with a backslash, so
+// the value is always valid UTF-8 and contains no tabs nor newlines.
+// Use DecodeQuotedBytes to decode it.
+func (e *Event) QuotedBytes(label string, value []byte) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = appendQuotedBytes(e.buf, value)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
 // Fmt appends a labeled formatted string value to Event.
 func (e *Event) Fmt(label, format string, a ...interface{}) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = append(e.buf, escape(fmt.Sprintf(format, a...))...)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	start := len(e.buf)
+	fmt.Fprintf((*appendWriter)(&e.buf), format, a...)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
 	return e
 }
 
@@ -83,10 +149,9 @@ func (e *Event) Bool(label string, value bool) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = strconv.AppendBool(e.buf, value)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendBool(e.buf, value)
+	e.endField()
 	return e
 }
 
@@ -95,10 +160,9 @@ func (e *Event) HexByte(label string, value byte) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = appendHexByte(e.buf, value)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendHexByte(e.buf, value)
+	e.endField()
 	return e
 }
 
@@ -133,10 +197,9 @@ func (e *Event) Int64(label string, value int64) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = strconv.AppendInt(e.buf, value, 10)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendInt64(e.buf, value)
+	e.endField()
 	return e
 }
 
@@ -165,10 +228,9 @@ func (e *Event) Uint64(label string, value uint64) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = strconv.AppendUint(e.buf, value, 10)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendUint64(e.buf, value)
+	e.endField()
 	return e
 }
 
@@ -177,10 +239,9 @@ func (e *Event) Float32(label string, value float32) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = strconv.AppendFloat(e.buf, float64(value), 'g', -1, 32)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendFloat(e.buf, float64(value), 32)
+	e.endField()
 	return e
 }
 
@@ -189,10 +250,9 @@ func (e *Event) Float64(label string, value float64) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = strconv.AppendFloat(e.buf, value, 'g', -1, 64)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendFloat(e.buf, value, 64)
+	e.endField()
 	return e
 }
 
@@ -203,13 +263,14 @@ func (e *Event) Time(label string, value time.Time, format string) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
+	e.appendKey(label)
 	if format == "" {
 		format = time.RFC3339
 	}
-	e.buf = append(e.buf, escape(value.Format(format))...)
-	e.buf = append(e.buf, '\t')
+	start := len(e.buf)
+	e.buf = value.AppendFormat(e.buf, format)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
 	return e
 }
 
@@ -222,10 +283,557 @@ func (e *Event) UTCTime(label string, value time.Time) *Event {
 	if !e.enabled {
 		return e
 	}
-	e.buf = append(e.buf, label...)
-	e.buf = append(e.buf, ':')
-	e.buf = appendUTCTime(e.buf, value)
-	e.buf = append(e.buf, '\t')
+	e.appendKey(label)
+	e.buf = e.logger.encoder.AppendUTCTime(e.buf, value)
+	e.endField()
+	return e
+}
+
+// Duration appends a labeled duration value to Event.
+// The format is set with SetDurationFormat and the default format is
+// the same as time.Duration.String.
+func (e *Event) Duration(label string, value time.Duration) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	switch e.logger.durationFormat {
+	case DurationFormatSeconds:
+		e.buf = e.logger.encoder.AppendFloat(e.buf, value.Seconds(), 64)
+	case DurationFormatMillis:
+		e.buf = e.logger.encoder.AppendInt64(e.buf, value.Milliseconds())
+	default:
+		start := len(e.buf)
+		e.buf = appendDuration(e.buf, value)
+		e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	}
+	e.endField()
+	return e
+}
+
+// IP appends a labeled IP address value to Event.
+// The value is in the same format as net.IP.String.
+func (e *Event) IP(label string, value net.IP) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = appendIP(e.buf, value)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
+// IPNet appends a labeled IP network value to Event.
+// The value is in the same format as net.IPNet.String.
+func (e *Event) IPNet(label string, value *net.IPNet) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = appendIPNet(e.buf, value)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
+// IPAddr appends a labeled IP address value to Event.
+// The value is in the same format as netip.Addr.String, except that
+// the zero Addr is written as an empty string.
+func (e *Event) IPAddr(label string, value netip.Addr) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = value.AppendTo(e.buf)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
+// IPPrefix appends a labeled IP prefix value to Event.
+// The value is in the same format as netip.Prefix.String.
+func (e *Event) IPPrefix(label string, value netip.Prefix) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = value.AppendTo(e.buf)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
+// URL appends a labeled URL value to Event.
+// The value is in the same format as url.URL.Redacted, that is,
+// the password is replaced with "xxxxx". Also, the values of query
+// parameters whose names match the patterns set with SetRedactURLQuery
+// are replaced with the redaction mask.
+func (e *Event) URL(label string, value *url.URL) *Event {
+	if !e.enabled {
+		return e
+	}
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = appendURL(e.buf, value, e.logger.redactQueryPatterns, e.logger.redactMask)
+	e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+	return e
+}
+
+// Strings appends a labeled list of strings to Event.
+// The JSON encoder writes the list as a JSON array. Other encoders write
+// the elements separated by commas, and commas and backslashes in
+// elements are escaped with a backslash. Use SplitList to decode it.
+func (e *Event) Strings(label string, values []string) *Event {
+	if !e.enabled {
+		return e
+	}
+	enc := e.logger.encoder
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = enc.AppendBeginArray(e.buf)
+	for i, v := range values {
+		if i > 0 {
+			e.buf = enc.AppendArraySeparator(e.buf)
+		}
+		e.buf = enc.AppendArrayString(e.buf, v)
+	}
+	e.buf = enc.AppendEndArray(e.buf, start, len(values))
+	e.endField()
+	return e
+}
+
+// Errs appends a labeled list of error messages to Event.
+// The format is the same as Strings, and nil errors are written as
+// empty strings.
+func (e *Event) Errs(label string, errs []error) *Event {
+	if !e.enabled {
+		return e
+	}
+	enc := e.logger.encoder
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = enc.AppendBeginArray(e.buf)
+	for i, err := range errs {
+		if i > 0 {
+			e.buf = enc.AppendArraySeparator(e.buf)
+		}
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+		}
+		e.buf = enc.AppendArrayString(e.buf, msg)
+	}
+	e.buf = enc.AppendEndArray(e.buf, start, len(errs))
+	e.endField()
+	return e
+}
+
+// Ints appends a labeled list of int values to Event.
+// The format is the same as Strings. Use ParseIntList to decode it.
+func (e *Event) Ints(label string, values []int) *Event {
+	if !e.enabled {
+		return e
+	}
+	enc := e.logger.encoder
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = enc.AppendBeginArray(e.buf)
+	for i, v := range values {
+		if i > 0 {
+			e.buf = enc.AppendArraySeparator(e.buf)
+		}
+		e.buf = enc.AppendInt64(e.buf, int64(v))
+	}
+	e.buf = enc.AppendEndArray(e.buf, start, len(values))
+	e.endField()
+	return e
+}
+
+// Int64s appends a labeled list of int64 values to Event.
+// The format is the same as Strings. Use ParseIntList to decode it.
+func (e *Event) Int64s(label string, values []int64) *Event {
+	if !e.enabled {
+		return e
+	}
+	enc := e.logger.encoder
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = enc.AppendBeginArray(e.buf)
+	for i, v := range values {
+		if i > 0 {
+			e.buf = enc.AppendArraySeparator(e.buf)
+		}
+		e.buf = enc.AppendInt64(e.buf, v)
+	}
+	e.buf = enc.AppendEndArray(e.buf, start, len(values))
+	e.endField()
+	return e
+}
+
+// Uint64s appends a labeled list of uint64 values to Event.
+// The format is the same as Strings. Use ParseUintList to decode it.
+func (e *Event) Uint64s(label string, values []uint64) *Event {
+	if !e.enabled {
+		return e
+	}
+	enc := e.logger.encoder
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = enc.AppendBeginArray(e.buf)
+	for i, v := range values {
+		if i > 0 {
+			e.buf = enc.AppendArraySeparator(e.buf)
+		}
+		e.buf = enc.AppendUint64(e.buf, v)
+	}
+	e.buf = enc.AppendEndArray(e.buf, start, len(values))
+	e.endField()
+	return e
+}
+
+// Float64s appends a labeled list of float64 values to Event.
+// The format is the same as Strings, and the elements are in the same
+// format as Float64. Use ParseFloatList to decode it.
+func (e *Event) Float64s(label string, values []float64) *Event {
+	if !e.enabled {
+		return e
+	}
+	enc := e.logger.encoder
+	e.appendKey(label)
+	start := len(e.buf)
+	e.buf = enc.AppendBeginArray(e.buf)
+	for i, v := range values {
+		if i > 0 {
+			e.buf = enc.AppendArraySeparator(e.buf)
+		}
+		e.buf = enc.AppendFloat(e.buf, v, 64)
+	}
+	e.buf = enc.AppendEndArray(e.buf, start, len(values))
+	e.endField()
+	return e
+}
+
+// LTSVMarshaler is the interface implemented by types which can append
+// their own value representation for Event.Any.
+// MarshalLTSV appends the raw value to buf and returns the extended
+// buffer. The value is escaped by the encoder afterwards, so it must not
+// be escaped by MarshalLTSV.
+type LTSVMarshaler interface {
+	MarshalLTSV(buf []byte) []byte
+}
+
+// Any appends a labeled value of an arbitrary type to Event.
+// The value is appended with the typed method for its type, like Int64
+// for int64 and Err for error, and values of types implementing
+// LTSVMarshaler are appended with MarshalLTSV. Values of types
+// implementing fmt.Stringer are appended with Stringer, and values of
+// other types are formatted with the "%v" verb of fmt as a last resort.
+// time.Time values are appended in time.RFC3339Nano format and []byte
+// values are appended in hex format. Nil pointers are appended as
+// "<nil>" without calling their methods.
+func (e *Event) Any(label string, value interface{}) *Event {
+	if !e.enabled {
+		return e
+	}
+	switch v := value.(type) {
+	case nil:
+		return e.String(label, "<nil>")
+	case string:
+		return e.String(label, v)
+	case bool:
+		return e.Bool(label, v)
+	case int:
+		return e.Int(label, v)
+	case int8:
+		return e.Int8(label, v)
+	case int16:
+		return e.Int16(label, v)
+	case int32:
+		return e.Int32(label, v)
+	case int64:
+		return e.Int64(label, v)
+	case uint:
+		return e.Uint(label, v)
+	case uint8:
+		return e.Uint8(label, v)
+	case uint16:
+		return e.Uint16(label, v)
+	case uint32:
+		return e.Uint32(label, v)
+	case uint64:
+		return e.Uint64(label, v)
+	case float32:
+		return e.Float32(label, v)
+	case float64:
+		return e.Float64(label, v)
+	case time.Time:
+		return e.Time(label, v, time.RFC3339Nano)
+	case time.Duration:
+		return e.Duration(label, v)
+	case []byte:
+		return e.HexBytes(label, v)
+	case net.IP:
+		return e.IP(label, v)
+	case *net.IPNet:
+		return e.IPNet(label, v)
+	case netip.Addr:
+		return e.IPAddr(label, v)
+	case netip.Prefix:
+		return e.IPPrefix(label, v)
+	case *url.URL:
+		return e.URL(label, v)
+	case []string:
+		return e.Strings(label, v)
+	case []error:
+		return e.Errs(label, v)
+	case []int:
+		return e.Ints(label, v)
+	case []int64:
+		return e.Int64s(label, v)
+	case []uint64:
+		return e.Uint64s(label, v)
+	case []float64:
+		return e.Float64s(label, v)
+	case LTSVMarshaler:
+		if isNilPointer(v) {
+			return e.String(label, "<nil>")
+		}
+		e.appendKey(label)
+		start := len(e.buf)
+		e.buf = v.MarshalLTSV(e.buf)
+		e.buf = e.logger.encoder.EncodeStringAt(e.buf, start)
+		e.endField()
+		return e
+	case error:
+		if isNilPointer(v) {
+			return e.String(label, "<nil>")
+		}
+		return e.appendErr(label, v, 1)
+	case fmt.Stringer:
+		if isNilPointer(v) {
+			return e.String(label, "<nil>")
+		}
+		return e.Stringer(label, v)
+	default:
+		return e.Fmt(label, "%v", v)
+	}
+}
+
+// Func calls f with this Event if this Event is enabled.
+// Use this to append fields which are expensive to build, without
+// paying the cost when the level is disabled, like:
+//
+//   ltsvlog.Logger.Debug().Func(func(e *ltsvlog.Event) {
+//       e.String("dump", dump(req))
+//   }).Log()
+func (e *Event) Func(f func(e *Event)) *Event {
+	if !e.enabled {
+		return e
+	}
+	f(e)
+	return e
+}
+
+// Lazy appends a labeled string value returned from f to Event.
+// f is called only if this Event is enabled.
+func (e *Event) Lazy(label string, f func() string) *Event {
+	if !e.enabled {
+		return e
+	}
+	return e.String(label, f())
+}
+
+// StringIf appends a labeled string value to Event only if cond is true.
+func (e *Event) StringIf(cond bool, label, value string) *Event {
+	if !cond {
+		return e
+	}
+	return e.String(label, value)
+}
+
+// When calls f with this Event if cond is true and this Event is enabled.
+func (e *Event) When(cond bool, f func(e *Event)) *Event {
+	if !cond || !e.enabled {
+		return e
+	}
+	f(e)
+	return e
+}
+
+// Namespace starts a namespace of fields. The labels of fields appended
+// after this call until End is called are prefixed with name and the
+// separator set with SetNamespaceSeparator, like "upstream.addr".
+// Namespaces can be nested, and the labels in nested namespaces are
+// prefixed with all names of the enclosing namespaces.
+func (e *Event) Namespace(name string) *Event {
+	if !e.enabled {
+		return e
+	}
+	if e.logger.labelValidation != LabelValidationNone {
+		name = e.logger.checkLabel(name)
+	}
+	e.prefixEnds = append(e.prefixEnds, len(e.prefix))
+	e.prefix = append(e.prefix, name...)
+	e.prefix = append(e.prefix, e.logger.namespaceSeparator...)
+	return e
+}
+
+// End ends the innermost namespace started with Namespace.
+// It does nothing if no namespace is started.
+func (e *Event) End() *Event {
+	if n := len(e.prefixEnds); n > 0 {
+		e.prefix = e.prefix[:e.prefixEnds[n-1]]
+		e.prefixEnds = e.prefixEnds[:n-1]
+	}
+	return e
+}
+
+// isNilPointer returns whether or not v is a nil pointer. Methods of nil
+// pointers may panic, so Any writes them as fmt does for nil pointers
+// whose methods panic.
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// appendWriter is an io.Writer which appends written bytes to the slice.
+type appendWriter []byte
+
+func (w *appendWriter) Write(p []byte) (n int, err error) {
+	*w = append(*w, p...)
+	return len(p), nil
+}
+
+func (e *Event) resetFields() {
+	e.fieldsStart = len(e.buf)
+	e.msgStart = -1
+	e.msgEnd = -1
+	e.redactStart = -1
+	e.prefix = e.prefix[:0]
+	e.prefixEnds = e.prefixEnds[:0]
+}
+
+func (e *Event) appendKey(label string) {
+	if e.logger.labelValidation != LabelValidationNone {
+		label = e.logger.checkLabel(label)
+	}
+	if len(e.prefix) > 0 {
+		e.appendPrefixedKey(label)
+		return
+	}
+	isMsg := e.logger.trackMsg && e.msgStart < 0 && label == e.logger.msgLabel
+	if isMsg {
+		e.msgStart = len(e.buf)
+	}
+	if !isMsg || !e.logger.msgNoLabel {
+		e.buf = e.logger.encoder.AppendKey(e.buf, label)
+	}
+	if e.logger.redactor != nil && e.logger.redactor.match(label) {
+		e.redactStart = len(e.buf)
+	}
+}
+
+// appendPrefixedKey appends the label prefixed with the namespace prefix.
+// Redaction patterns are matched against both the label and the
+// prefixed label.
+func (e *Event) appendPrefixedKey(label string) {
+	e.buf = e.logger.encoder.AppendPrefixedKey(e.buf, e.prefix, label)
+	if r := e.logger.redactor; r != nil {
+		// Build the prefixed label after the prefix temporarily, so that
+		// no memory is allocated if the result is cached.
+		n := len(e.prefix)
+		e.prefix = append(e.prefix, label...)
+		matched := r.match(label) || r.matchBytes(e.prefix)
+		e.prefix = e.prefix[:n]
+		if matched {
+			e.redactStart = len(e.buf)
+		}
+	}
+}
+
+func (e *Event) endField() {
+	if e.redactStart >= 0 {
+		e.buf = e.logger.redactor.redact(e.logger.encoder, e.buf, e.redactStart)
+		e.redactStart = -1
+	}
+	e.buf = e.logger.encoder.AppendEndField(e.buf)
+	if e.msgStart >= 0 && e.msgEnd < 0 {
+		e.msgEnd = len(e.buf)
+	}
+}
+
+// message returns the message field including the label, or nil if
+// the message is not tracked or not appended.
+func (e *Event) message() []byte {
+	if e.msgEnd < 0 {
+		return nil
+	}
+	return e.buf[e.msgStart:e.msgEnd]
+}
+
+// moveMessageFirst moves the message field to just after the time and level.
+func (e *Event) moveMessageFirst() {
+	if e.logger.msgFirst && e.msgStart > e.fieldsStart {
+		rotate(e.buf[e.fieldsStart:e.msgEnd], e.msgStart-e.fieldsStart)
+	}
+}
+
+// rotate rotates b to the left by n bytes in place.
+func rotate(b []byte, n int) {
+	reverse(b[:n])
+	reverse(b[n:])
+	reverse(b)
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// Err appends a labeled error message to Event.
+// Nothing is appended if err is nil.
+//
+// The errors wrapped in err are walked with the Unwrap() error and
+// Unwrap() []error methods, so errors wrapped with fmt.Errorf("%w")
+// or joined with errors.Join are supported. The pairs of labels and
+// values of the errors implementing LabeledValuer are appended, and
+// if SetErrCauses is enabled, the messages of the wrapped errors are
+// also appended.
+//
+// Also, if the error or one of the wrapped errors has the call stack
+// created with github.com/hnakamur/errstack, the call stack value with
+// the "stack" label is appended. Otherwise if SetErrStackDepth is set,
+// the call stack is captured at the call site of Err.
+func (e *Event) Err(label string, err error) *Event {
+	return e.appendErr(label, err, 1)
+}
+
+// appendErr appends err as Err does.
+// The skip argument is the count of stack frames to skip for capturing
+// the call stack, with 0 identifying the caller of appendErr.
+func (e *Event) appendErr(label string, err error, skip int) *Event {
+	if !e.enabled || err == nil {
+		return e
+	}
+	e.String(label, err.Error())
+	if e.logger.errCauses {
+		e.appendErrCauses(label, err, 0)
+	}
+	e.appendErrLV(err, nil)
+	ff := errStack(err)
+	if len(ff) == 0 && e.logger.errStackDepth > 0 {
+		ff = e.logger.captureStack(skip + 1)
+	}
+	if len(ff) > 0 {
+		e.appendStack(ff)
+	}
 	return e
 }
 
@@ -241,12 +849,60 @@ func (e *Event) Format(s fmt.State, c rune) {
 	}
 }
 
+// Msg appends a message value with the message label, which is "msg"
+// by default and can be changed with SetMessageLabel, and writes this
+// event by calling Log. The message label is not prefixed in a
+// namespace.
+func (e *Event) Msg(msg string) {
+	if e.enabled {
+		e.prefix = e.prefix[:0]
+		e.String(e.logger.msgLabel, msg)
+	}
+	e.Log()
+}
+
+// Msgf appends a formatted message value with the message label and
+// writes this event by calling Log, like Msg.
+func (e *Event) Msgf(format string, a ...interface{}) {
+	if e.enabled {
+		e.prefix = e.prefix[:0]
+		e.Fmt(e.logger.msgLabel, format, a...)
+	}
+	e.Log()
+}
+
 // Log writes this event if the logger which created this event is enabled,
 // and puts the event back to the event pool.
+//
+// If the logger has a sampler, the event may be dropped.
+//
+// Log must be called only once for each Event, and the Event must not
+// be used after calling Log. Calling Log again for the same Event is
+// ignored unless the Event has been reused from the event pool.
 func (e *Event) Log() {
+	// logger is nil for disabledEvent and events which Log has already
+	// been called for.
+	if e.logger == nil {
+		return
+	}
+	if e.enabled && e.logger.sampler != nil && e.level != "Error" {
+		now := e.logger.now()
+		e.logger.reportSamplerDropped(now)
+		if !e.logger.sampler.sample(now, e.level, e.message()) {
+			e.enabled = false
+			if e.logger.sampler.droppedOne() {
+				e.logger.setSamplerTimer()
+			}
+		}
+	}
 	if e.enabled && len(e.buf) > 0 {
-		e.buf[len(e.buf)-1] = '\n'
+		e.moveMessageFirst()
+		e.buf = e.logger.encoder.AppendEndRecord(e.buf)
 		_, _ = e.logger.writer.Write(e.buf)
 	}
-	eventPool.Put(e)
+	e.logger = nil
+	e.enabled = false
+	if cap(e.buf) <= maxPooledBufSize {
+		eventPool.Put(e)
+	}
 }
@@ -0,0 +1,150 @@
+package ltsvlog
+
+import (
+	"io"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var (
+	benchTime   = time.Date(2017, 5, 21, 12, 44, 56, 987654321, time.UTC)
+	benchBytes  = []byte("\t\n")
+	benchIP     = net.ParseIP("2001:db8::1")
+	benchIPAddr = netip.MustParseAddr("192.0.2.1")
+	benchInts   = []int{1, 2, 3}
+	benchURL, _ = url.Parse("https://example.com/search?q=ltsv&token=secret#top")
+)
+
+var eventBenchCases = []struct {
+	name string
+	f    func(l *LTSVLogger)
+}{
+	{
+		name: "string",
+		f: func(l *LTSVLogger) {
+			l.Info().String("msg", "hello").String("key", "value").Log()
+		},
+	},
+	{
+		name: "string_escape",
+		f: func(l *LTSVLogger) {
+			l.Info().String("json", "{\n\t\"foo\": \"bar\\nbaz\"\n}\n").Log()
+		},
+	},
+	{
+		name: "int",
+		f: func(l *LTSVLogger) {
+			l.Info().Int("int", math.MaxInt32).Int64("int64", math.MinInt64).Uint64("uint64", math.MaxUint64).Log()
+		},
+	},
+	{
+		name: "float",
+		f: func(l *LTSVLogger) {
+			l.Info().Float32("float32", math.MaxFloat32).Float64("float64", math.Pi).Log()
+		},
+	},
+	{
+		name: "bool",
+		f: func(l *LTSVLogger) {
+			l.Info().Bool("bool", true).Log()
+		},
+	},
+	{
+		name: "hex_bytes",
+		f: func(l *LTSVLogger) {
+			l.Info().HexByte("byte", 'b').HexBytes("bytes", benchBytes).Log()
+		},
+	},
+	{
+		name: "base64_quoted_bytes",
+		f: func(l *LTSVLogger) {
+			l.Info().Base64Bytes("base64", benchBytes).QuotedBytes("quoted", benchBytes).Log()
+		},
+	},
+	{
+		name: "time",
+		f: func(l *LTSVLogger) {
+			l.Info().Time("time1", benchTime, time.RFC3339Nano).UTCTime("time2", benchTime).Log()
+		},
+	},
+	{
+		name: "duration",
+		f: func(l *LTSVLogger) {
+			l.Info().Duration("elapsed", 1500*time.Microsecond).Log()
+		},
+	},
+	{
+		name: "ip",
+		f: func(l *LTSVLogger) {
+			l.Info().IP("ip", benchIP).IPAddr("addr", benchIPAddr).Log()
+		},
+	},
+	{
+		name: "url",
+		f: func(l *LTSVLogger) {
+			l.Info().URL("url", benchURL).Log()
+		},
+	},
+	{
+		name: "list",
+		f: func(l *LTSVLogger) {
+			l.Info().Ints("ints", benchInts).Log()
+		},
+	},
+	{
+		name: "namespace",
+		f: func(l *LTSVLogger) {
+			l.Info().Namespace("upstream").String("addr", "192.0.2.1").Int("status", 200).End().Log()
+		},
+	},
+	{
+		name: "fmt",
+		f: func(l *LTSVLogger) {
+			l.Info().Fmt("pi", "%.2f", math.Pi).Log()
+		},
+	},
+}
+
+var encoderBenchCases = []struct {
+	name string
+	enc  Encoder
+}{
+	{name: "ltsv", enc: NewLTSVEncoder()},
+	{name: "json", enc: NewJSONEncoder()},
+	{name: "logfmt", enc: NewLogfmtEncoder()},
+}
+
+func TestEvent_ZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable with the race detector")
+	}
+	for _, ec := range encoderBenchCases {
+		logger := NewLTSVLogger(io.Discard, true, SetEncoder(ec.enc))
+		for _, tc := range eventBenchCases {
+			allocs := testing.AllocsPerRun(100, func() {
+				tc.f(logger)
+			})
+			if allocs != 0 {
+				t.Errorf("encoder=%s, case=%s, allocs got %v; want 0", ec.name, tc.name, allocs)
+			}
+		}
+	}
+}
+
+func BenchmarkEvent(b *testing.B) {
+	for _, ec := range encoderBenchCases {
+		logger := NewLTSVLogger(io.Discard, true, SetEncoder(ec.enc))
+		for _, tc := range eventBenchCases {
+			b.Run(ec.name+"/"+tc.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					tc.f(logger)
+				}
+			})
+		}
+	}
+}
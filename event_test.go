@@ -2,10 +2,16 @@ package ltsvlog
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"math"
 	"math/big"
+	"net"
+	"strconv"
 	"testing"
 	"time"
+
+	"github.com/hnakamur/errstack"
 )
 
 func TestEvent_Log(t *testing.T) {
@@ -167,6 +173,28 @@ func TestEvent_Log(t *testing.T) {
 			},
 			want: "level:Info\ttime2:2017-05-21T12:44:56.987654Z\n",
 		},
+		{
+			name: "err",
+			f: func(l *LTSVLogger) {
+				err := errstack.WithLV(errors.New("some error")).String("reqID", "req1")
+				l.Info().String("msg", "while processing job").Err("err", err).Int("jobID", 1).Log()
+			},
+			want: "level:Info\tmsg:while processing job\terr:some error\treqID:req1\tjobID:1\n",
+		},
+		{
+			name: "err_nil",
+			f: func(l *LTSVLogger) {
+				l.Info().Err("err", nil).Int("jobID", 1).Log()
+			},
+			want: "level:Info\tjobID:1\n",
+		},
+		{
+			name: "error_level",
+			f: func(l *LTSVLogger) {
+				l.Error().String("msg", "job failed").Err("cause", errors.New("some error")).Log()
+			},
+			want: "level:Error\tmsg:job failed\tcause:some error\n",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -216,3 +244,272 @@ func TestEvent_Log_NoLevel(t *testing.T) {
 		})
 	}
 }
+
+type testMarshaler struct{ x, y int }
+
+func (m testMarshaler) MarshalLTSV(buf []byte) []byte {
+	buf = strconv.AppendInt(buf, int64(m.x), 10)
+	buf = append(buf, '\t')
+	return strconv.AppendInt(buf, int64(m.y), 10)
+}
+
+func (m testMarshaler) String() string { return "unused" }
+
+type testStringer struct{}
+
+func (testStringer) String() string { return "stringer" }
+
+type testPtrStringer struct{ s string }
+
+func (p *testPtrStringer) String() string { return p.s }
+
+type testPtrError struct{ msg string }
+
+func (p *testPtrError) Error() string { return p.msg }
+
+func TestEvent_Any(t *testing.T) {
+	testCases := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: nil, want: "<nil>"},
+		{value: "foo", want: "foo"},
+		{value: true, want: "true"},
+		{value: int8(-8), want: "-8"},
+		{value: uint16(16), want: "16"},
+		{value: float32(1.5), want: "1.5"},
+		{value: time.Date(2017, 5, 21, 12, 44, 56, 987654321, time.UTC), want: "2017-05-21T12:44:56.987654321Z"},
+		{value: 1500 * time.Millisecond, want: "1.5s"},
+		{value: []byte("\x01\xff"), want: "0x01ff"},
+		{value: net.IPv4(192, 0, 2, 1), want: "192.0.2.1"},
+		{value: []string{"a", "b"}, want: "a,b"},
+		{value: testMarshaler{1, 2}, want: "1\\t2"},
+		{value: errors.New("some error"), want: "some error"},
+		{value: testStringer{}, want: "stringer"},
+		{value: struct{ A int }{1}, want: "{1}"},
+		{value: (*testPtrStringer)(nil), want: "<nil>"},
+		{value: (*testPtrError)(nil), want: "<nil>"},
+		{value: (*testMarshaler)(nil), want: "<nil>"},
+		{value: &testPtrStringer{"ptr"}, want: "ptr"},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		logger := NewLTSVLogger(&buf, false, SetTimeLabel(""), SetLevelLabel(""))
+		logger.Info().Any("v", tc.value).Log()
+		if got, want := buf.String(), "v:"+tc.want+"\n"; got != want {
+			t.Errorf("Any(%#v) got %q; want %q", tc.value, got, want)
+		}
+	}
+}
+
+func TestEvent_Namespace(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+		f    func(l *LTSVLogger)
+		want string
+	}{
+		{
+			name: "nested",
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "proxied").
+					Namespace("upstream").String("addr", "192.0.2.1:80").
+					Namespace("cache").Bool("hit", false).End().
+					Int("status", 502).End().
+					Namespace("client").String("addr", "198.51.100.1").End().
+					Int("status", 200).
+					Log()
+			},
+			want: "level:Info\tmsg:proxied\tupstream.addr:192.0.2.1:80\tupstream.cache.hit:false\tupstream.status:502\tclient.addr:198.51.100.1\tstatus:200\n",
+		},
+		{
+			name: "separator",
+			opts: []Option{SetNamespaceSeparator("_"), SetEncoder(NewJSONEncoder())},
+			f: func(l *LTSVLogger) {
+				l.Info().Namespace("upstream").Err("err", errors.New("timeout")).Log()
+			},
+			want: `{"level":"Info","upstream_err":"timeout"}` + "\n",
+		},
+		{
+			name: "invalidSeparator",
+			opts: []Option{SetNamespaceSeparator(":"), SetLabelValidation(LabelValidationSanitize)},
+			f: func(l *LTSVLogger) {
+				l.Info().Namespace("a").String("b", "c").Log()
+			},
+			want: "level:Info\ta_b:c\n",
+		},
+		{
+			name: "endWithoutNamespace",
+			f: func(l *LTSVLogger) {
+				l.Info().End().String("a", "b").Log()
+			},
+			want: "level:Info\ta:b\n",
+		},
+		{
+			name: "reset",
+			f: func(l *LTSVLogger) {
+				l.Info().Namespace("ns").String("a", "b").Log()
+				l.Info().String("a", "b").Log()
+			},
+			want: "level:Info\tns.a:b\nlevel:Info\ta:b\n",
+		},
+		{
+			name: "redact",
+			opts: []Option{SetRedactLabels("password", "upstream.*")},
+			f: func(l *LTSVLogger) {
+				l.Info().Namespace("client").String("password", "hunter2").String("user", "alice").End().
+					Namespace("upstream").String("addr", "192.0.2.1:80").End().
+					String("addr", "198.51.100.1").
+					Log()
+			},
+			want: "level:Info\tclient.password:[REDACTED]\tclient.user:alice\tupstream.addr:[REDACTED]\taddr:198.51.100.1\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLTSVLogger(&buf, false, append([]Option{SetTimeLabel("")}, tc.opts...)...)
+			tc.f(logger)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvent_Conditional(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLTSVLogger(&buf, false, SetTimeLabel(""))
+	called := 0
+	f := func(e *Event) {
+		called++
+		e.Int("called", called)
+	}
+	lazy := func() string {
+		called++
+		return "lazy"
+	}
+
+	logger.Debug().Func(f).Lazy("lazy", lazy).When(true, f).Log()
+	if called != 0 {
+		t.Errorf("functions called %d times for disabled event; want 0", called)
+	}
+
+	logger.Info().Func(f).Lazy("lazy", lazy).
+		StringIf(true, "if1", "yes").StringIf(false, "if2", "no").
+		When(true, f).When(false, f).
+		Log()
+	want := "level:Info\tcalled:1\tlazy:lazy\tif1:yes\tcalled:3\n"
+	if got := buf.String(); got != want {
+		t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, want)
+	}
+}
+
+func TestEvent_Enabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLTSVLogger(&buf, false, SetTimeLabel(""))
+	discard := &Discard{}
+	testCases := []struct {
+		name string
+		ev   *Event
+		want bool
+	}{
+		{name: "info", ev: logger.Info(), want: true},
+		{name: "disabledDebug", ev: logger.Debug(), want: false},
+		{name: "discardInfo", ev: discard.Info(), want: false},
+		{name: "discardErrEvent", ev: discard.ErrEvent(errors.New("some error")), want: false},
+	}
+	for _, tc := range testCases {
+		if got := tc.ev.Enabled(); got != tc.want {
+			t.Errorf("%s: Enabled got %v; want %v", tc.name, got, tc.want)
+		}
+		if !tc.want && tc.ev != disabledEvent {
+			t.Errorf("%s: got %p; want the shared disabled event", tc.name, tc.ev)
+		}
+		tc.ev.Log()
+	}
+}
+
+func TestEvent_Log_Twice(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLTSVLogger(&buf, false, SetTimeLabel(""))
+	ev := logger.Info().String("a", "b")
+	ev.Log()
+	ev.Log()
+	if got, want := buf.String(), "level:Info\ta:b\n"; got != want {
+		t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, want)
+	}
+}
+
+func TestEvent_Disabled_ZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable with the race detector")
+	}
+	logger := NewLTSVLogger(io.Discard, false)
+	discard := &Discard{}
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Debug().String("a", "b").Int("c", 1).Log()
+		discard.Info().String("a", "b").Log()
+	})
+	if allocs != 0 {
+		t.Errorf("allocs got %v; want 0", allocs)
+	}
+}
+
+func TestEvent_Msg(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+		f    func(l *LTSVLogger)
+		want string
+	}{
+		{
+			name: "msg",
+			f: func(l *LTSVLogger) {
+				l.Info().String("reqID", "req1").Msg("request done")
+			},
+			want: "level:Info\treqID:req1\tmsg:request done\n",
+		},
+		{
+			name: "msgf",
+			f: func(l *LTSVLogger) {
+				l.Info().Namespace("req").String("id", "req1").Msgf("took %dms", 12)
+			},
+			want: "level:Info\treq.id:req1\tmsg:took 12ms\n",
+		},
+		{
+			name: "messageFirst",
+			opts: []Option{SetMessageFirst(true), SetMessageLabel("message")},
+			f: func(l *LTSVLogger) {
+				l.Info().String("reqID", "req1").Msg("request done")
+			},
+			want: "level:Info\tmessage:request done\treqID:req1\n",
+		},
+		{
+			name: "console",
+			opts: []Option{SetEncoder(NewConsoleEncoder()), SetLevelLabel("level"), SetMessageLabel("message")},
+			f: func(l *LTSVLogger) {
+				l.Info().String("reqID", "req1").Msg("request done")
+			},
+			want: "INFO  request done reqID=req1\n",
+		},
+		{
+			name: "disabled",
+			f: func(l *LTSVLogger) {
+				l.Debug().Msg("never written")
+				l.Debug().Msgf("never %s", "written")
+			},
+			want: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLTSVLogger(&buf, false, append([]Option{SetTimeLabel("")}, tc.opts...)...)
+			tc.f(logger)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -10,10 +10,11 @@ import (
 func ExampleNewLTSVLogger() {
 	// Change the global logger to a logger which does not print level values.
 	ltsvlog.Logger = ltsvlog.NewLTSVLogger(os.Stdout, true, ltsvlog.SetLevelLabel(""))
-	// Output:
 
 	// Actually we don't test the results.
 	// This example is added just for document purpose.
+
+	// Output:
 }
 
 func ExampleLTSVLogger_Debug() {
@@ -46,7 +47,7 @@ func ExampleLTSVLogger_Info() {
 	// This example is added just for document purpose.
 }
 
-func ExampleLTSVLoggerErr() {
+func ExampleLTSVLogger_Err_errorf() {
 	b := func() error {
 		return errstack.New("some error")
 	}
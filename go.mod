@@ -1,5 +1,5 @@
 module github.com/hnakamur/ltsvlog/v3
 
-go 1.13
+go 1.18
 
 require github.com/hnakamur/errstack v0.2.0
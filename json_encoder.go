@@ -0,0 +1,204 @@
+package ltsvlog
+
+import (
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+type jsonEncoder struct{}
+
+// NewJSONEncoder returns the encoder which writes logs in JSON lines format.
+// Each log record is written as a JSON object in one line.
+//
+// String values are escaped as JSON strings, integer, float and bool
+// values are written as JSON numbers and bools. Since NaN and infinity
+// cannot be represented as JSON numbers, they are written as strings.
+// Hex bytes and time values are written as strings in the same format as
+// the LTSV encoder. Lists are written as JSON arrays.
+func NewJSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+func (jsonEncoder) AppendBeginRecord(buf []byte) []byte {
+	return append(buf, '{')
+}
+
+func (jsonEncoder) AppendEndRecord(buf []byte) []byte {
+	if len(buf) > 0 && buf[len(buf)-1] == ',' {
+		buf[len(buf)-1] = '}'
+	} else {
+		buf = append(buf, '}')
+	}
+	return append(buf, '\n')
+}
+
+func (jsonEncoder) AppendKey(buf []byte, label string) []byte {
+	buf = appendJSONString(buf, label)
+	return append(buf, ':')
+}
+
+func (jsonEncoder) AppendPrefixedKey(buf []byte, prefix []byte, label string) []byte {
+	start := len(buf)
+	buf = append(buf, prefix...)
+	buf = append(buf, label...)
+	buf = jsonEncodeAt(buf, start)
+	return append(buf, ':')
+}
+
+func (jsonEncoder) AppendEndField(buf []byte) []byte {
+	return append(buf, ',')
+}
+
+func (jsonEncoder) AppendString(buf []byte, value string) []byte {
+	return appendJSONString(buf, value)
+}
+
+func (jsonEncoder) EncodeStringAt(buf []byte, start int) []byte {
+	return jsonEncodeAt(buf, start)
+}
+
+func (jsonEncoder) AppendBool(buf []byte, value bool) []byte {
+	return strconv.AppendBool(buf, value)
+}
+
+func (jsonEncoder) AppendInt64(buf []byte, value int64) []byte {
+	return strconv.AppendInt(buf, value, 10)
+}
+
+func (jsonEncoder) AppendUint64(buf []byte, value uint64) []byte {
+	return strconv.AppendUint(buf, value, 10)
+}
+
+func (jsonEncoder) AppendFloat(buf []byte, value float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(value):
+		return append(buf, `"NaN"`...)
+	case math.IsInf(value, 1):
+		return append(buf, `"+Inf"`...)
+	case math.IsInf(value, -1):
+		return append(buf, `"-Inf"`...)
+	}
+	return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+}
+
+func (jsonEncoder) AppendHexByte(buf []byte, value byte) []byte {
+	buf = append(buf, '"')
+	buf = appendHexByte(buf, value)
+	return append(buf, '"')
+}
+
+func (jsonEncoder) AppendHexBytes(buf []byte, value []byte) []byte {
+	buf = append(buf, '"')
+	buf = appendHexBytes(buf, value)
+	return append(buf, '"')
+}
+
+func (jsonEncoder) AppendUTCTime(buf []byte, value time.Time) []byte {
+	buf = append(buf, '"')
+	buf = appendUTCTime(buf, value)
+	return append(buf, '"')
+}
+
+func (jsonEncoder) AppendBeginArray(buf []byte) []byte {
+	return append(buf, '[')
+}
+
+func (jsonEncoder) AppendArraySeparator(buf []byte) []byte {
+	return append(buf, ',')
+}
+
+func (jsonEncoder) AppendArrayString(buf []byte, value string) []byte {
+	return appendJSONString(buf, value)
+}
+
+func (jsonEncoder) AppendEndArray(buf []byte, start, n int) []byte {
+	return append(buf, ']')
+}
+
+// appendJSONString appends s as a quoted JSON string.
+func appendJSONString(buf []byte, s string) []byte {
+	start := len(buf)
+	buf = append(buf, s...)
+	return jsonEncodeAt(buf, start)
+}
+
+// jsonEncodeAt converts buf[start:] to a quoted JSON string in place.
+func jsonEncodeAt(buf []byte, start int) []byte {
+	if !jsonNeedsEscape(buf[start:]) {
+		buf = append(buf, 0)
+		copy(buf[start+1:], buf[start:len(buf)-1])
+		buf[start] = '"'
+		return append(buf, '"')
+	}
+
+	// Append the escaped string after the raw string, and then move it
+	// to the start position. Note raw still refers to the raw string even
+	// if buf is reallocated in appendJSONBytes.
+	raw := buf[start:]
+	end := len(buf)
+	buf = appendJSONBytes(buf, raw)
+	n := copy(buf[start:], buf[end:])
+	return buf[:start+n]
+}
+
+func jsonNeedsEscape(s []byte) bool {
+	for _, b := range s {
+		if b < 0x20 || b == '"' || b == '\\' || b >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// appendJSONBytes appends s as a quoted JSON string.
+// Invalid UTF-8 sequences are replaced with U+FFFD as encoding/json does.
+func appendJSONBytes(buf []byte, s []byte) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', digits[b>>4], digits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRune(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are valid in JSON but not in JavaScript,
+		// so escape them as encoding/json does.
+		if c == '\u2028' || c == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', digits[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
@@ -0,0 +1,139 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetEncoder(NewJSONEncoder()))
+
+	testCases := []struct {
+		name string
+		f    func(l *LTSVLogger)
+		want string
+	}{
+		{
+			name: "debug_string",
+			f: func(l *LTSVLogger) {
+				l.Debug().String("msg", "hello").Log()
+			},
+			want: `{"level":"Debug","msg":"hello"}` + "\n",
+		},
+		{
+			name: "string_escape",
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "a\"b\\c\td\ne\x01f\u2028g\xff").Log()
+			},
+			want: `{"level":"Info","msg":"a\"b\\c\td\ne\u0001f\u2028g\ufffd"}` + "\n",
+		},
+		{
+			name: "label_escape",
+			f: func(l *LTSVLogger) {
+				l.Info().String("a\"b", "c").Log()
+			},
+			want: `{"level":"Info","a\"b":"c"}` + "\n",
+		},
+		{
+			name: "stringer",
+			f: func(l *LTSVLogger) {
+				l.Info().Stringer("msg", big.NewInt(123)).Log()
+			},
+			want: `{"level":"Info","msg":"123"}` + "\n",
+		},
+		{
+			name: "bool",
+			f: func(l *LTSVLogger) {
+				l.Info().Bool("active", true).Bool("enabled", false).Log()
+			},
+			want: `{"level":"Info","active":true,"enabled":false}` + "\n",
+		},
+		{
+			name: "bytes",
+			f: func(l *LTSVLogger) {
+				l.Info().HexByte("byte", 'b').HexBytes("bytes", []byte("\t\n")).Log()
+			},
+			want: `{"level":"Info","byte":"0x62","bytes":"0x090a"}` + "\n",
+		},
+		{
+			name: "int_uint",
+			f: func(l *LTSVLogger) {
+				l.Info().Int64("min_int64", math.MinInt64).Uint64("max_uint64", math.MaxUint64).Log()
+			},
+			want: `{"level":"Info","min_int64":-9223372036854775808,"max_uint64":18446744073709551615}` + "\n",
+		},
+		{
+			name: "float",
+			f: func(l *LTSVLogger) {
+				l.Info().Float32("f32", 1.5).Float64("f64", math.MaxFloat64).
+					Float64("nan", math.NaN()).Float64("inf", math.Inf(1)).Float64("ninf", math.Inf(-1)).Log()
+			},
+			want: `{"level":"Info","f32":1.5,"f64":1.7976931348623157e+308,"nan":"NaN","inf":"+Inf","ninf":"-Inf"}` + "\n",
+		},
+		{
+			name: "time",
+			f: func(l *LTSVLogger) {
+				t := time.Date(2017, 5, 21, 12, 44, 56, 987654321, time.UTC)
+				l.Info().Time("time1", t, time.RFC822Z).UTCTime("time2", t).Log()
+			},
+			want: `{"level":"Info","time1":"21 May 17 12:44 +0000","time2":"2017-05-21T12:44:56.987654Z"}` + "\n",
+		},
+		{
+			name: "err",
+			f: func(l *LTSVLogger) {
+				l.Err(errstack.WithLV(errors.New("some\terror")).String("reqID", "req\"1"))
+			},
+			want: `{"level":"Error","err":"some\terror","reqID":"req\"1"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.f(logger)
+			got := buf.String()
+			if got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSONEncoder_NoPrefix(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetLevelLabel(""), SetEncoder(NewJSONEncoder()))
+
+	logger.Info().Log()
+	logger.Info().String("msg", "hello").Log()
+	want := "{}\n" + `{"msg":"hello"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+}
+
+func TestJSONEncoder_Allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable with the race detector")
+	}
+	ltsvLogger := NewLTSVLogger(io.Discard, true)
+	jsonLogger := NewLTSVLogger(io.Discard, true, SetEncoder(NewJSONEncoder()))
+	logFunc := func(l *LTSVLogger) func() {
+		return func() {
+			l.Info().String("msg", "hello").Int64("n", 1).Bool("b", true).
+				HexBytes("bytes", []byte("ab")).UTCTime("t", time.Time{}).Log()
+		}
+	}
+	ltsvAllocs := testing.AllocsPerRun(100, logFunc(ltsvLogger))
+	jsonAllocs := testing.AllocsPerRun(100, logFunc(jsonLogger))
+	if jsonAllocs > ltsvAllocs {
+		t.Errorf("JSON allocs %v is larger than LTSV allocs %v", jsonAllocs, ltsvAllocs)
+	}
+}
@@ -0,0 +1,101 @@
+package ltsvlog
+
+// LabelValidation is the mode of validating labels.
+type LabelValidation int
+
+const (
+	// LabelValidationNone does not validate labels. This is the default.
+	LabelValidationNone LabelValidation = iota
+	// LabelValidationReport writes invalid labels as they are, and only
+	// reports them to the handler set with SetInvalidLabelHandler.
+	LabelValidationReport
+	// LabelValidationSanitize replaces invalid characters in labels with "_".
+	// An empty label is replaced with "_".
+	LabelValidationSanitize
+	// LabelValidationEscape replaces invalid characters in labels with "%"
+	// followed by two hex digits like URL encoding. "%" itself is not
+	// a valid character, so it is escaped too.
+	// An empty label is replaced with "_".
+	LabelValidationEscape
+)
+
+// SetLabelValidation returns the option function to set the mode of
+// validating labels against the character set of labels in the LTSV
+// spec, that is, alphanumerics, "_", "." and "-".
+//
+// The labels of Event fields and labeled values of errors written with Err
+// are validated. The labels for the time, level and caller must be valid
+// and are not validated.
+func SetLabelValidation(v LabelValidation) Option {
+	return func(l *LTSVLogger) {
+		l.labelValidation = v
+	}
+}
+
+// SetInvalidLabelHandler returns the option function to set the function
+// which is called with the original label when an invalid label is found.
+// If the label validation mode is LabelValidationNone, it is changed to
+// LabelValidationReport.
+func SetInvalidLabelHandler(handler func(label string)) Option {
+	return func(l *LTSVLogger) {
+		l.invalidLabelHandler = handler
+	}
+}
+
+// isValidLabelByte returns whether or not b is a valid character of labels
+// in the LTSV spec.
+func isValidLabelByte(b byte) bool {
+	return ('0' <= b && b <= '9') || ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') ||
+		b == '_' || b == '.' || b == '-'
+}
+
+func isValidLabel(label string) bool {
+	if label == "" {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		if !isValidLabelByte(label[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkLabel validates the label and returns the label to write.
+func (l *LTSVLogger) checkLabel(label string) string {
+	if isValidLabel(label) {
+		return label
+	}
+	if l.invalidLabelHandler != nil {
+		l.invalidLabelHandler(label)
+	}
+	if label == "" {
+		if l.labelValidation == LabelValidationReport {
+			return label
+		}
+		return "_"
+	}
+	switch l.labelValidation {
+	case LabelValidationSanitize:
+		b := []byte(label)
+		for i, c := range b {
+			if !isValidLabelByte(c) {
+				b[i] = '_'
+			}
+		}
+		return string(b)
+	case LabelValidationEscape:
+		b := make([]byte, 0, len(label)+8)
+		for i := 0; i < len(label); i++ {
+			if c := label[i]; isValidLabelByte(c) {
+				b = append(b, c)
+			} else {
+				b = append(b, '%', upperDigits[c>>4], upperDigits[c&0xF])
+			}
+		}
+		return string(b)
+	}
+	return label
+}
+
+const upperDigits = "0123456789ABCDEF"
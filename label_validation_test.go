@@ -0,0 +1,71 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestSetLabelValidation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{
+			name: "none",
+			want: "level:Info\tok:1\tbad:label:2\t:3\n",
+		},
+		{
+			name:    "sanitize",
+			options: []Option{SetLabelValidation(LabelValidationSanitize)},
+			want:    "level:Info\tok:1\tbad_label:2\t_:3\n",
+		},
+		{
+			name:    "escape",
+			options: []Option{SetLabelValidation(LabelValidationEscape)},
+			want:    "level:Info\tok:1\tbad%3Alabel:2\t_:3\n",
+		},
+		{
+			name:    "report",
+			options: []Option{SetLabelValidation(LabelValidationReport)},
+			want:    "level:Info\tok:1\tbad:label:2\t:3\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			// We don't print time fields to make it easy to compare test results.
+			logger := NewLTSVLogger(buf, true, append(tc.options, SetTimeLabel(""))...)
+			logger.Info().Int("ok", 1).Int("bad:label", 2).Int("", 3).Log()
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetInvalidLabelHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var invalidLabels []string
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""),
+		SetLabelValidation(LabelValidationSanitize),
+		SetInvalidLabelHandler(func(label string) {
+			invalidLabels = append(invalidLabels, label)
+		}))
+	logger.Info().String("user id", "1").String("a\tb", "2").Log()
+	logger.Err(errstack.WithLV(errors.New("some error")).String("req:id", "req1"))
+
+	want := "level:Info\tuser_id:1\ta_b:2\n" +
+		"level:Error\terr:some error\treq_id:req1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	wantLabels := []string{"user id", "a\tb", "req:id"}
+	if !reflect.DeepEqual(invalidLabels, wantLabels) {
+		t.Errorf("invalid labels mismatch, got %q; want %q", invalidLabels, wantLabels)
+	}
+}
@@ -0,0 +1,131 @@
+package ltsvlog
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidList is the error returned when a list value has a backslash
+// which is not followed by "," or "\\", or it has "\\0" which is not the
+// whole value.
+var ErrInvalidList = errors.New("ltsvlog: invalid list value")
+
+// emptyList is the value of an empty list in text encoders.
+// It is distinguished from a list which consists of an empty string,
+// which is written as an empty value.
+const emptyList = `\0`
+
+// appendEndList appends emptyList if the list has no elements.
+func appendEndList(buf []byte, n int) []byte {
+	if n == 0 {
+		return append(buf, emptyList...)
+	}
+	return buf
+}
+
+// appendListString appends s as an element of a list value.
+// Commas and backslashes are escaped with a backslash.
+func appendListString(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; b == ',' || b == '\\' {
+			buf = append(buf, '\\', b)
+		} else {
+			buf = append(buf, b)
+		}
+	}
+	return buf
+}
+
+// SplitList splits a list value written with Event.Strings, Event.Errs
+// and other list methods into elements. The value must be unescaped with
+// UnescapeValue beforehand, which is done by ParseLine.
+//
+// Elements are separated by commas, and commas and backslashes in
+// elements are escaped with a backslash. An empty list is written as
+// "\\0", and an empty value is a list which consists of an empty string.
+// Nil slices are written as empty lists.
+func SplitList(value string) ([]string, error) {
+	if value == emptyList {
+		return []string{}, nil
+	}
+	var elems []string
+	var b []byte
+	escaped := false
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 >= len(value) || (value[i+1] != ',' && value[i+1] != '\\') {
+				return nil, ErrInvalidList
+			}
+			if !escaped {
+				b = b[:0]
+				escaped = true
+			}
+			b = append(b, value[start:i]...)
+			b = append(b, value[i+1])
+			i++
+			start = i + 1
+		case ',':
+			elems = append(elems, listElem(b, value[start:i], escaped))
+			escaped = false
+			start = i + 1
+		}
+	}
+	return append(elems, listElem(b, value[start:], escaped)), nil
+}
+
+func listElem(b []byte, rest string, escaped bool) string {
+	if !escaped {
+		return rest
+	}
+	return string(append(b, rest...))
+}
+
+// ParseIntList parses a list value written with Event.Ints or
+// Event.Int64s. See SplitList for the format.
+func ParseIntList(value string) ([]int64, error) {
+	elems, err := SplitList(value)
+	if err != nil {
+		return nil, err
+	}
+	ints := make([]int64, len(elems))
+	for i, s := range elems {
+		if ints[i], err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return ints, nil
+}
+
+// ParseUintList parses a list value written with Event.Uint64s.
+// See SplitList for the format.
+func ParseUintList(value string) ([]uint64, error) {
+	elems, err := SplitList(value)
+	if err != nil {
+		return nil, err
+	}
+	uints := make([]uint64, len(elems))
+	for i, s := range elems {
+		if uints[i], err = strconv.ParseUint(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return uints, nil
+}
+
+// ParseFloatList parses a list value written with Event.Float64s.
+// See SplitList for the format.
+func ParseFloatList(value string) ([]float64, error) {
+	elems, err := SplitList(value)
+	if err != nil {
+		return nil, err
+	}
+	floats := make([]float64, len(elems))
+	for i, s := range elems {
+		if floats[i], err = strconv.ParseFloat(s, 64); err != nil {
+			return nil, err
+		}
+	}
+	return floats, nil
+}
@@ -0,0 +1,123 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  []string
+		err   error
+	}{
+		{value: `\0`, want: []string{}},
+		{value: "", want: []string{""}},
+		{value: "a", want: []string{"a"}},
+		{value: "a,,b", want: []string{"a", "", "b"}},
+		{value: ",", want: []string{"", ""}},
+		{value: `a\,b,c\\,\\\,`, want: []string{"a,b", `c\`, `\,`}},
+		{value: `a\`, err: ErrInvalidList},
+		{value: `a\n`, err: ErrInvalidList},
+		{value: `a,\0`, err: ErrInvalidList},
+	}
+	for _, tc := range testCases {
+		got, err := SplitList(tc.value)
+		if err != tc.err {
+			t.Errorf("SplitList(%q) err got %v; want %v", tc.value, err, tc.err)
+		} else if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("SplitList(%q) got %q; want %q", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestEvent_List(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""))
+	logger.Info().
+		Strings("strings", []string{"a,b", "c\\d", "\te\n", ""}).
+		Errs("errs", []error{errors.New("err1"), nil}).
+		Ints("ints", []int{1, -2}).
+		Int64s("int64s", []int64{math.MinInt64}).
+		Uint64s("uint64s", []uint64{math.MaxUint64, 0}).
+		Float64s("float64s", []float64{1.5, math.Inf(1)}).
+		Strings("empty", nil).
+		Strings("oneEmpty", []string{""}).
+		Log()
+	want := "level:Info\tstrings:a\\\\,b,c\\\\\\\\d,\\te\\n,\terrs:err1,\tints:1,-2\tint64s:-9223372036854775808\tuint64s:18446744073709551615,0\tfloat64s:1.5,+Inf\tempty:\\\\0\toneEmpty:\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("log unmatch,\n got=%q,\nwant=%q", got, want)
+	}
+
+	fields, err := ParseLine(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]string)
+	for _, f := range fields {
+		values[f.Label] = f.Value
+	}
+
+	if got, err := SplitList(values["strings"]); err != nil || !reflect.DeepEqual(got, []string{"a,b", "c\\d", "\te\n", ""}) {
+		t.Errorf("strings got %q, %v", got, err)
+	}
+	if got, err := SplitList(values["errs"]); err != nil || !reflect.DeepEqual(got, []string{"err1", ""}) {
+		t.Errorf("errs got %q, %v", got, err)
+	}
+	if got, err := ParseIntList(values["ints"]); err != nil || !reflect.DeepEqual(got, []int64{1, -2}) {
+		t.Errorf("ints got %v, %v", got, err)
+	}
+	if got, err := ParseIntList(values["int64s"]); err != nil || !reflect.DeepEqual(got, []int64{math.MinInt64}) {
+		t.Errorf("int64s got %v, %v", got, err)
+	}
+	if got, err := ParseUintList(values["uint64s"]); err != nil || !reflect.DeepEqual(got, []uint64{math.MaxUint64, 0}) {
+		t.Errorf("uint64s got %v, %v", got, err)
+	}
+	if got, err := ParseFloatList(values["float64s"]); err != nil || !reflect.DeepEqual(got, []float64{1.5, math.Inf(1)}) {
+		t.Errorf("float64s got %v, %v", got, err)
+	}
+	if got, err := ParseIntList(values["empty"]); err != nil || len(got) != 0 {
+		t.Errorf("empty got %v, %v", got, err)
+	}
+	if got, err := SplitList(values["oneEmpty"]); err != nil || !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("oneEmpty got %q, %v", got, err)
+	}
+}
+
+func TestEvent_List_Encoders(t *testing.T) {
+	testCases := []struct {
+		name string
+		enc  Encoder
+		want string
+	}{
+		{
+			name: "json",
+			enc:  NewJSONEncoder(),
+			want: `{"level":"Info","strings":["a,b","\"c\""],"ints":[1,-2],"floats":[1.5,"NaN"],"empty":[],"oneEmpty":[""]}` + "\n",
+		},
+		{
+			name: "logfmt",
+			enc:  NewLogfmtEncoder(),
+			want: `level=Info strings="a\\,b,\"c\"" ints=1,-2 floats=1.5,NaN empty=\0 oneEmpty=` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetEncoder(tc.enc))
+			logger.Info().
+				Strings("strings", []string{"a,b", `"c"`}).
+				Ints("ints", []int{1, -2}).
+				Float64s("floats", []float64{1.5, math.NaN()}).
+				Ints("empty", []int{}).
+				Strings("oneEmpty", []string{""}).
+				Log()
+			if got := buf.String(); got != tc.want {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
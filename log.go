@@ -13,15 +13,22 @@
 // So you must not contain a colon character in labels.
 // This is not checked in this library for performance reason,
 // so it is your responsibility not to contain a colon character in labels.
+// If labels may be built dynamically, you can enable validation of labels
+// with SetLabelValidation.
 //
 // Newline, tab, and backslach characters in values are escaped with
 // "\\n", "\\t", and "\\\\" respectively. Show the example for Event.String.
+//
+// Logs can be written in other formats with the same Event API by
+// setting an Encoder with SetEncoder. For example, NewJSONEncoder
+// writes each log record as a JSON object in one line,
+// NewLogfmtEncoder writes logs in logfmt format, and
+// NewConsoleEncoder writes human readable logs for development.
 package ltsvlog
 
 import (
 	"io"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/hnakamur/errstack"
@@ -43,7 +50,37 @@ type LTSVLogger struct {
 	debugEnabled     bool
 	timeLabel        string
 	levelLabel       string
+	encoder          Encoder
+	timeFormatter    *timeFormatter
+	now              func() time.Time
 	appendPrefixFunc appendPrefixFuncType
+	msgLabel         string
+	msgFirst         bool
+	trackMsg         bool
+	msgNoLabel       bool
+	callerLabel      string
+	callerSkip       int
+	callerFunc       bool
+	callerCache      *callerCache
+	redactPatterns   []string
+	redactMask       string
+	redactHash       bool
+	redactor         *redactor
+	sampler          *sampler
+	errDedup         *errDeduper
+	errCauses        bool
+	errStackDepth    int
+	errStackFilter   func(f errstack.Frame) bool
+	stackFormat      StackFormat
+	stackCompact     bool
+	stackMaxFrames   int
+	durationFormat   DurationFormat
+
+	redactQueryPatterns []string
+	namespaceSeparator  string
+
+	labelValidation     LabelValidation
+	invalidLabelHandler func(label string)
 }
 
 // Option is the function type to set an option of LTSVLogger
@@ -65,12 +102,64 @@ func SetLevelLabel(label string) Option {
 	}
 }
 
+// SetNamespaceSeparator returns the option function to set the separator
+// between the names of namespaces started with Event.Namespace and
+// labels. The default separator is ".".
+// If label validation is enabled with SetLabelValidation, the separator
+// is validated in the same way as labels.
+func SetNamespaceSeparator(sep string) Option {
+	return func(l *LTSVLogger) {
+		l.namespaceSeparator = sep
+	}
+}
+
+// SetMessageLabel returns the option function to set the message label,
+// which is used by Event.Msg and Event.Msgf, SetMessageFirst, the sampler
+// and the console encoder. The default label is "msg".
+func SetMessageLabel(label string) Option {
+	return func(l *LTSVLogger) {
+		l.msgLabel = label
+	}
+}
+
+// SetMessageFirst returns the option function to set whether or not
+// the message field is moved to just after the time and level fields,
+// wherever it is appended, so that logs are easy to grep.
+// This is always enabled with the console encoder.
+func SetMessageFirst(enabled bool) Option {
+	return func(l *LTSVLogger) {
+		l.msgFirst = enabled
+	}
+}
+
+// SetClock returns the option function to set the function which
+// returns the current time for the time field.
+// The default is time.Now. This is useful for writing logs with fixed
+// times in tests or with historical times in replay tools.
+func SetClock(now func() time.Time) Option {
+	return func(l *LTSVLogger) {
+		l.now = now
+	}
+}
+
+// SetEncoder returns the option function to set the encoder.
+// The default encoder is the one returned by NewLTSVEncoder.
+// For example, use NewJSONEncoder to write logs in JSON lines format.
+func SetEncoder(enc Encoder) Option {
+	return func(l *LTSVLogger) {
+		l.encoder = enc
+	}
+}
+
 const (
-	defaultTimeLabel  = "time"
-	defaultLevelLabel = "level"
+	defaultTimeLabel    = "time"
+	defaultLevelLabel   = "level"
+	defaultMessageLabel = "msg"
+
+	defaultNamespaceSeparator = "."
 )
 
-var defaultappendPrefixFuncType = appendPrefixFunc(defaultTimeLabel, defaultLevelLabel)
+var defaultappendPrefixFuncType = appendPrefixFunc(ltsvEncoder{}, defaultTimeLabel, defaultLevelLabel, nil, time.Now)
 
 // NewLTSVLogger creates a LTSV logger with the default time and value format.
 //
@@ -80,21 +169,53 @@ var defaultappendPrefixFuncType = appendPrefixFunc(defaultTimeLabel, defaultLeve
 // The time format is RFC3339 with microseconds in UTC timezone.
 // This format is the same as "2006-01-02T15:04:05.000000Z" in the
 // go time format https://golang.org/pkg/time/#Time.Format
+// You can change the format with SetTimeFormat or SetTimeLayout.
 //
 // The second value is the log level with the default label "level".
 func NewLTSVLogger(w io.Writer, debugEnabled bool, options ...Option) *LTSVLogger {
 	l := &LTSVLogger{
-		writer:           w,
-		debugEnabled:     debugEnabled,
-		timeLabel:        defaultTimeLabel,
-		levelLabel:       defaultLevelLabel,
-		appendPrefixFunc: defaultappendPrefixFuncType,
+		writer:             w,
+		debugEnabled:       debugEnabled,
+		timeLabel:          defaultTimeLabel,
+		levelLabel:         defaultLevelLabel,
+		encoder:            ltsvEncoder{},
+		appendPrefixFunc:   defaultappendPrefixFuncType,
+		msgLabel:           defaultMessageLabel,
+		redactMask:         defaultRedactMask,
+		errStackFilter:     defaultErrStackFilter,
+		namespaceSeparator: defaultNamespaceSeparator,
 	}
 	for _, o := range options {
 		o(l)
 	}
-	if l.timeLabel != defaultTimeLabel || l.levelLabel != defaultLevelLabel {
-		l.appendPrefixFunc = appendPrefixFunc(l.timeLabel, l.levelLabel)
+	clockSet := l.now != nil
+	if !clockSet {
+		l.now = time.Now
+	}
+	if c, ok := l.encoder.(consoleEncoder); ok {
+		if !c.color {
+			c.color = isTerminal(l.writer) && os.Getenv("NO_COLOR") == ""
+		}
+		l.encoder = c
+		l.msgFirst = true
+		l.msgNoLabel = true
+	}
+	l.trackMsg = l.msgFirst || l.sampler != nil
+	if l.invalidLabelHandler != nil && l.labelValidation == LabelValidationNone {
+		l.labelValidation = LabelValidationReport
+	}
+	if l.labelValidation != LabelValidationNone && l.namespaceSeparator != "" {
+		l.namespaceSeparator = l.checkLabel(l.namespaceSeparator)
+	}
+	if l.callerLabel != "" {
+		l.callerCache = newCallerCache(l.callerFunc)
+	}
+	if len(l.redactPatterns) > 0 {
+		l.redactor = newRedactor(l.redactPatterns, l.redactMask, l.redactHash)
+	}
+	if _, ok := l.encoder.(ltsvEncoder); !ok || l.timeLabel != defaultTimeLabel || l.levelLabel != defaultLevelLabel ||
+		l.timeFormatter != nil || clockSet {
+		l.appendPrefixFunc = appendPrefixFunc(l.encoder, l.timeLabel, l.levelLabel, l.timeFormatter, l.now)
 	}
 	return l
 }
@@ -112,16 +233,24 @@ func (l *LTSVLogger) DebugEnabled() bool {
 // Debug returns a new Event for writing a Debug level log.
 // This Event is returned from the internal event pool, so be sure
 // to call Log() to put this event back to the event pool.
+// If the debug level is disabled, the shared disabled Event is returned
+// without using the event pool.
 //
 // Note there still exists the cost of evaluating argument values if the debug level is disabled, even though those arguments are not used.
 // So guarding with if and DebugEnabled is recommended.
 func (l *LTSVLogger) Debug() *Event {
+	if !l.debugEnabled {
+		return disabledEvent
+	}
 	ev := eventPool.Get().(*Event)
 	ev.logger = l
-	ev.enabled = l.debugEnabled
+	ev.enabled = true
+	ev.level = "Debug"
 	ev.buf = ev.buf[:0]
-	if ev.enabled {
-		ev.buf = l.appendPrefixFunc(ev.buf, "Debug")
+	ev.buf = l.appendPrefixFunc(ev.buf, "Debug")
+	ev.resetFields()
+	if l.callerLabel != "" {
+		ev.buf = l.appendCaller(ev.buf, 1)
 	}
 	return ev
 }
@@ -133,81 +262,127 @@ func (l *LTSVLogger) Info() *Event {
 	ev := eventPool.Get().(*Event)
 	ev.logger = l
 	ev.enabled = true
+	ev.level = "Info"
 	ev.buf = ev.buf[:0]
 	ev.buf = l.appendPrefixFunc(ev.buf, "Info")
+	ev.resetFields()
+	if l.callerLabel != "" {
+		ev.buf = l.appendCaller(ev.buf, 1)
+	}
 	return ev
 }
 
 // Err writes a log for an error with the error level.
 // It writes the err.Error() value with the label "err".
 //
-// If the result of github.com/hnakamur/errstack.LV(err)
-// is not empty, then the pairs of labels and values are
-// appended.
+// The labeled values, the call stack and optionally the messages of
+// the wrapped errors are appended as Event.Err does. The call stack is
+// captured at the call site if SetErrStackDepth is set and err has none.
+//
+// Nothing is written if err is nil.
 //
-// Also, if the result of github.com/hnakamur/errstack.Stack(err)
-// is not empty, then the call stack value with the "stack"
-// label is appended.
+// Duplicate errors are suppressed if SetErrDedup is used.
 func (l *LTSVLogger) Err(err error) {
-	buf := make([]byte, 0, 8192)
-	buf = l.appendPrefixFunc(buf, "Error")
-	buf = append(buf, "err:"...)
-	buf = append(buf, err.Error()...)
-	if lv := errstack.LV(err); len(lv) > 0 {
-		for i := 0; i < len(lv); i += 2 {
-			buf = append(buf, '\t')
-			buf = append(buf, lv[i]...)
-			buf = append(buf, ':')
-			buf = append(buf, escape(lv[i+1])...)
-		}
+	if err == nil {
+		return
+	}
+	l.errEvent(err).Log()
+}
+
+// Error returns a new Event for writing a Error level log.
+// Use Event.Err to append errors to the Event.
+// This Event is returned from the internal event pool, so be sure
+// to call Log() to put this event back to the event pool.
+func (l *LTSVLogger) Error() *Event {
+	ev := eventPool.Get().(*Event)
+	ev.logger = l
+	ev.enabled = true
+	ev.level = "Error"
+	ev.buf = ev.buf[:0]
+	ev.buf = l.appendPrefixFunc(ev.buf, "Error")
+	ev.resetFields()
+	if l.callerLabel != "" {
+		ev.buf = l.appendCaller(ev.buf, 1)
 	}
-	if ff := errstack.Stack(err); len(ff) > 0 {
-		buf = append(buf, "\tstack:"...)
-		for i, f := range ff {
-			if i > 0 {
-				buf = append(buf, ' ')
-			}
-			buf = append(buf, f.String()...)
+	return ev
+}
+
+// ErrEvent returns a new Event for writing a log for an error with the
+// error level. The values written by Err are appended to the Event,
+// and you can append additional values before calling Log like:
+//
+//   ltsvlog.Logger.ErrEvent(err).String("jobID", jobID).Log()
+//
+// This Event is returned from the internal event pool, so be sure
+// to call Log() to put this event back to the event pool.
+func (l *LTSVLogger) ErrEvent(err error) *Event {
+	return l.errEvent(err)
+}
+
+func (l *LTSVLogger) errEvent(err error) *Event {
+	if l.errDedup != nil && err != nil {
+		write, expired, setTimer := l.errDedup.check(l.now(), err)
+		for _, e := range expired {
+			l.writeErrRepeated(e)
+		}
+		if setTimer {
+			l.setErrDedupTimer(l.errDedup.window)
+		}
+		if !write {
+			return disabledEvent
 		}
 	}
-	buf = append(buf, '\n')
-	_, _ = l.writer.Write(buf)
+
+	ev := eventPool.Get().(*Event)
+	ev.logger = l
+	ev.enabled = true
+	ev.level = "Error"
+	ev.buf = ev.buf[:0]
+	ev.buf = l.appendPrefixFunc(ev.buf, "Error")
+	ev.resetFields()
+	if l.callerLabel != "" {
+		ev.buf = l.appendCaller(ev.buf, 2)
+	}
+	return ev.appendErr("err", err, 2)
 }
 
-func appendPrefixFunc(timeLabel, levelLabel string) appendPrefixFuncType {
+func appendPrefixFunc(enc Encoder, timeLabel, levelLabel string, tf *timeFormatter, now func() time.Time) appendPrefixFuncType {
+	if c, ok := enc.(consoleEncoder); ok {
+		return c.appendPrefixFunc(timeLabel, levelLabel, tf, now)
+	}
+	if tf == nil {
+		tf = &timeFormatters[TimeFormatUTCMicro]
+	}
 	if timeLabel != "" && levelLabel != "" {
 		return func(buf []byte, level string) []byte {
-			buf = append(buf, timeLabel...)
-			buf = append(buf, ':')
-			now := time.Now().UTC()
-			buf = appendUTCTime(buf, now)
-			buf = append(buf, '\t')
-			buf = append(buf, levelLabel...)
-			buf = append(buf, ':')
-			buf = append(buf, level...)
-			buf = append(buf, '\t')
+			buf = enc.AppendBeginRecord(buf)
+			buf = enc.AppendKey(buf, timeLabel)
+			buf = tf.appendValue(enc, buf, now())
+			buf = enc.AppendEndField(buf)
+			buf = enc.AppendKey(buf, levelLabel)
+			buf = enc.AppendString(buf, level)
+			buf = enc.AppendEndField(buf)
 			return buf
 		}
 	} else if timeLabel != "" && levelLabel == "" {
 		return func(buf []byte, level string) []byte {
-			buf = append(buf, timeLabel...)
-			buf = append(buf, ':')
-			now := time.Now().UTC()
-			buf = appendUTCTime(buf, now)
-			buf = append(buf, '\t')
+			buf = enc.AppendBeginRecord(buf)
+			buf = enc.AppendKey(buf, timeLabel)
+			buf = tf.appendValue(enc, buf, now())
+			buf = enc.AppendEndField(buf)
 			return buf
 		}
 	} else if timeLabel == "" && levelLabel != "" {
 		return func(buf []byte, level string) []byte {
-			buf = append(buf, levelLabel...)
-			buf = append(buf, ':')
-			buf = append(buf, level...)
-			buf = append(buf, '\t')
+			buf = enc.AppendBeginRecord(buf)
+			buf = enc.AppendKey(buf, levelLabel)
+			buf = enc.AppendString(buf, level)
+			buf = enc.AppendEndField(buf)
 			return buf
 		}
 	} else {
 		return func(buf []byte, level string) []byte {
-			return buf
+			return enc.AppendBeginRecord(buf)
 		}
 	}
 }
@@ -245,10 +420,27 @@ func itoa(buf []byte, i int, wid int) {
 	buf[bp] = byte('0' + i)
 }
 
-var escaper = strings.NewReplacer("\t", "\\t", "\n", "\\n", "\\", "\\\\")
-
-func escape(s string) string {
-	return escaper.Replace(s)
+// appendEscaped appends s with tab, newline and backslash characters
+// escaped. It appends s as is if there is no character to escape.
+func appendEscaped(buf []byte, s string) []byte {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		var c byte
+		switch s[i] {
+		case '\t':
+			c = 't'
+		case '\n':
+			c = 'n'
+		case '\\':
+			c = '\\'
+		default:
+			continue
+		}
+		buf = append(buf, s[start:i]...)
+		buf = append(buf, '\\', c)
+		start = i + 1
+	}
+	return append(buf, s[start:]...)
 }
 
 var digits = []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a', 'b', 'c', 'd', 'e', 'f'}
@@ -287,22 +479,24 @@ func (*Discard) DebugEnabled() bool { return false }
 // Note there still exists the cost of evaluating argument values, even though they are not used.
 // Guarding with if and DebugEnabled is recommended.
 func (*Discard) Debug() *Event {
-	ev := eventPool.Get().(*Event)
-	ev.logger = nil
-	ev.enabled = false
-	ev.buf = ev.buf[:0]
-	return ev
+	return disabledEvent
 }
 
 // Info prints nothing.
 // Note there still exists the cost of evaluating argument values, even though they are not used.
 func (*Discard) Info() *Event {
-	ev := eventPool.Get().(*Event)
-	ev.logger = nil
-	ev.enabled = false
-	ev.buf = ev.buf[:0]
-	return ev
+	return disabledEvent
+}
+
+// Error prints nothing.
+func (*Discard) Error() *Event {
+	return disabledEvent
 }
 
 // Err prints nothing.
 func (*Discard) Err(err error) {}
+
+// ErrEvent prints nothing.
+func (*Discard) ErrEvent(err error) *Event {
+	return disabledEvent
+}
@@ -2,8 +2,12 @@ package ltsvlog
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"testing"
 	"time"
+
+	"github.com/hnakamur/errstack"
 )
 
 func TestAppendTime(t *testing.T) {
@@ -24,3 +28,124 @@ func TestAppendTime(t *testing.T) {
 		}
 	}
 }
+
+func TestSetClock(t *testing.T) {
+	now := time.Date(2017, 5, 7, 22, 13, 59, 987654321, time.UTC)
+	clock := func() time.Time { return now }
+
+	testCases := []struct {
+		name    string
+		options []Option
+		f       func(l *LTSVLogger)
+		want    string
+	}{
+		{
+			name:    "ltsv",
+			options: []Option{SetClock(clock)},
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "hello").Log()
+			},
+			want: "time:2017-05-07T22:13:59.987654Z\tlevel:Info\tmsg:hello\n",
+		},
+		{
+			name:    "ltsv_err",
+			options: []Option{SetClock(clock)},
+			f: func(l *LTSVLogger) {
+				l.Err(errors.New("some error"))
+			},
+			want: "time:2017-05-07T22:13:59.987654Z\tlevel:Error\terr:some error\n",
+		},
+		{
+			name:    "json",
+			options: []Option{SetClock(clock), SetEncoder(NewJSONEncoder())},
+			f: func(l *LTSVLogger) {
+				l.Debug().String("msg", "hello").Log()
+			},
+			want: `{"time":"2017-05-07T22:13:59.987654Z","level":"Debug","msg":"hello"}` + "\n",
+		},
+		{
+			name:    "console",
+			options: []Option{SetClock(func() time.Time { return now.Local() }), SetEncoder(NewConsoleEncoder())},
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "hello").Log()
+			},
+			want: now.Local().Format("15:04:05.000") + " INFO  hello\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := NewLTSVLogger(buf, true, tc.options...)
+			tc.f(logger)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAppendEscaped(t *testing.T) {
+	testCases := []struct {
+		buf  string
+		val  string
+		want string
+	}{
+		{buf: "", val: "", want: ""},
+		{buf: "a:", val: "hello", want: "a:hello"},
+		{buf: "a:", val: "\tb\nc\\", want: `a:\tb\nc\\`},
+		{buf: "a:", val: "{\n\t\"foo\": \"bar\\nbaz\"\n}\n", want: `a:{\n\t"foo": "bar\\nbaz"\n}\n`},
+	}
+	for _, c := range testCases {
+		got := string(appendEscaped([]byte(c.buf), c.val))
+		if got != c.want {
+			t.Errorf("got=%s, want=%s", got, c.want)
+		}
+	}
+}
+
+func TestLTSVLogger_ErrEvent(t *testing.T) {
+	buf := new(bytes.Buffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""))
+
+	err := errstack.WithLV(errors.New("some error")).String("reqID", "req1")
+	logger.ErrEvent(err).String("jobID", "job1").Int("retry", 2).Log()
+	want := "level:Error\terr:some error\treqID:req1\tjobID:job1\tretry:2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestLTSVLogger_Err_Stack(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""))
+
+	err := errstack.New("some error")
+	logger.Err(err)
+	var want []byte
+	want = append(want, "level:Error\terr:some error\tstack:"...)
+	for i, f := range errstack.Stack(err) {
+		if i > 0 {
+			want = append(want, ' ')
+		}
+		want = append(want, f.String()...)
+	}
+	want = append(want, '\n')
+	if got := buf.String(); got != string(want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestLTSVLogger_Err_Allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable with the race detector")
+	}
+	logger := NewLTSVLogger(io.Discard, true)
+	err := errstack.WithLV(errstack.New("some error")).String("reqID", "req1")
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Err(err)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs got %v; want 0", allocs)
+	}
+}
@@ -0,0 +1,115 @@
+package ltsvlog
+
+import (
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+type logfmtEncoder struct{}
+
+// NewLogfmtEncoder returns the encoder which writes logs in logfmt format.
+//
+// Each field is written as label=value separated by a space.
+// Values which contain spaces, double quotes, equal signs, control
+// characters or invalid UTF-8 sequences are quoted and escaped in the
+// same way as JSON strings. Empty values are written as label= without
+// quotes.
+func NewLogfmtEncoder() Encoder {
+	return logfmtEncoder{}
+}
+
+func (logfmtEncoder) AppendBeginRecord(buf []byte) []byte {
+	return buf
+}
+
+func (logfmtEncoder) AppendEndRecord(buf []byte) []byte {
+	if len(buf) > 0 && buf[len(buf)-1] == ' ' {
+		buf[len(buf)-1] = '\n'
+		return buf
+	}
+	return append(buf, '\n')
+}
+
+func (logfmtEncoder) AppendKey(buf []byte, label string) []byte {
+	buf = append(buf, label...)
+	return append(buf, '=')
+}
+
+func (logfmtEncoder) AppendPrefixedKey(buf []byte, prefix []byte, label string) []byte {
+	buf = append(buf, prefix...)
+	buf = append(buf, label...)
+	return append(buf, '=')
+}
+
+func (logfmtEncoder) AppendEndField(buf []byte) []byte {
+	return append(buf, ' ')
+}
+
+func (e logfmtEncoder) AppendString(buf []byte, value string) []byte {
+	start := len(buf)
+	buf = append(buf, value...)
+	return e.EncodeStringAt(buf, start)
+}
+
+func (logfmtEncoder) EncodeStringAt(buf []byte, start int) []byte {
+	if needsQuote(buf[start:]) {
+		return jsonEncodeAt(buf, start)
+	}
+	return buf
+}
+
+// needsQuote returns whether or not s contains spaces, double quotes,
+// equal signs, control characters or invalid UTF-8 sequences.
+func needsQuote(s []byte) bool {
+	for _, b := range s {
+		if b <= ' ' || b == '"' || b == '=' || b == 0x7f {
+			return true
+		}
+	}
+	return !utf8.Valid(s)
+}
+
+func (logfmtEncoder) AppendBool(buf []byte, value bool) []byte {
+	return strconv.AppendBool(buf, value)
+}
+
+func (logfmtEncoder) AppendInt64(buf []byte, value int64) []byte {
+	return strconv.AppendInt(buf, value, 10)
+}
+
+func (logfmtEncoder) AppendUint64(buf []byte, value uint64) []byte {
+	return strconv.AppendUint(buf, value, 10)
+}
+
+func (logfmtEncoder) AppendFloat(buf []byte, value float64, bitSize int) []byte {
+	return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+}
+
+func (logfmtEncoder) AppendHexByte(buf []byte, value byte) []byte {
+	return appendHexByte(buf, value)
+}
+
+func (logfmtEncoder) AppendHexBytes(buf []byte, value []byte) []byte {
+	return appendHexBytes(buf, value)
+}
+
+func (logfmtEncoder) AppendUTCTime(buf []byte, value time.Time) []byte {
+	return appendUTCTime(buf, value)
+}
+
+func (logfmtEncoder) AppendBeginArray(buf []byte) []byte {
+	return buf
+}
+
+func (logfmtEncoder) AppendArraySeparator(buf []byte) []byte {
+	return append(buf, ',')
+}
+
+func (logfmtEncoder) AppendArrayString(buf []byte, value string) []byte {
+	return appendListString(buf, value)
+}
+
+func (e logfmtEncoder) AppendEndArray(buf []byte, start, n int) []byte {
+	return e.EncodeStringAt(appendEndList(buf, n), start)
+}
@@ -0,0 +1,83 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetEncoder(NewLogfmtEncoder()))
+
+	testCases := []struct {
+		name string
+		f    func(l *LTSVLogger)
+		want string
+	}{
+		{
+			name: "debug_string",
+			f: func(l *LTSVLogger) {
+				l.Debug().String("msg", "hello").Log()
+			},
+			want: "level=Debug msg=hello\n",
+		},
+		{
+			name: "quote",
+			f: func(l *LTSVLogger) {
+				l.Info().String("msg", "hello world").String("a", `b"c`).String("d", "e=f").
+					String("g", "h\ti").String("empty", "").String("path", "/a/b").Log()
+			},
+			want: `level=Info msg="hello world" a="b\"c" d="e=f" g="h\ti" empty= path=/a/b` + "\n",
+		},
+		{
+			name: "numbers",
+			f: func(l *LTSVLogger) {
+				l.Info().Int("n", -1).Uint("u", 2).Float64("f", 1.5).Float64("nan", math.NaN()).Bool("b", true).Log()
+			},
+			want: "level=Info n=-1 u=2 f=1.5 nan=NaN b=true\n",
+		},
+		{
+			name: "time",
+			f: func(l *LTSVLogger) {
+				t := time.Date(2017, 5, 21, 12, 44, 56, 987654321, time.UTC)
+				l.Info().Time("time1", t, time.RFC822Z).UTCTime("time2", t).Log()
+			},
+			want: `level=Info time1="21 May 17 12:44 +0000" time2=2017-05-21T12:44:56.987654Z` + "\n",
+		},
+		{
+			name: "err",
+			f: func(l *LTSVLogger) {
+				l.Err(errstack.WithLV(errors.New("some error")).String("reqID", "req1"))
+			},
+			want: `level=Error err="some error" reqID=req1` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.f(logger)
+			got := buf.String()
+			if got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLogfmtEncoder_Labels(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel("ts"), SetLevelLabel("lvl"), SetEncoder(NewLogfmtEncoder()))
+
+	logger.Info().String("msg", "hello").Log()
+	got := buf.String()
+	if len(got) != len("ts=2017-05-21T12:44:56.987654Z lvl=Info msg=hello\n") ||
+		got[:3] != "ts=" || got[30:] != " lvl=Info msg=hello\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
@@ -0,0 +1,178 @@
+// Package ltsvlogtest provides loggers for testing code which writes logs
+// with ltsvlog.
+package ltsvlogtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	ltsvlog "github.com/hnakamur/ltsvlog/v3"
+)
+
+const levelLabel = "level"
+
+// Entry is a log record recorded by an observer logger.
+type Entry struct {
+	// Level is "Debug", "Info" or "Error".
+	Level string
+	// Fields are the fields except the level in the order in the log line.
+	Fields []ltsvlog.Field
+}
+
+// Value returns the value of the first field with the label.
+func (e Entry) Value(label string) (value string, ok bool) {
+	for _, f := range e.Fields {
+		if f.Label == label {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// ObservedLogs is a collection of log records written to an observer logger.
+// It is safe for concurrent use.
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// New creates a logger which records logs in memory and the ObservedLogs
+// to query recorded logs.
+//
+// The options are applied before the options for the observer, so the
+// time label, the level label and the encoder cannot be changed.
+func New(debugEnabled bool, options ...ltsvlog.Option) (*ltsvlog.LTSVLogger, *ObservedLogs) {
+	o := &ObservedLogs{}
+	options = append(options,
+		ltsvlog.SetTimeLabel(""),
+		ltsvlog.SetLevelLabel(levelLabel),
+		ltsvlog.SetEncoder(ltsvlog.NewLTSVEncoder()))
+	return ltsvlog.NewLTSVLogger(observerWriter{o}, debugEnabled, options...), o
+}
+
+type observerWriter struct {
+	o *ObservedLogs
+}
+
+func (w observerWriter) Write(p []byte) (n int, err error) {
+	fields, err := ltsvlog.ParseLine(string(p))
+	if err != nil {
+		return 0, err
+	}
+	var e Entry
+	if len(fields) > 0 && fields[0].Label == levelLabel {
+		e.Level = fields[0].Value
+		fields = fields[1:]
+	}
+	e.Fields = fields
+	w.o.add(e)
+	return len(p), nil
+}
+
+func (o *ObservedLogs) add(e Entry) {
+	o.mu.Lock()
+	o.entries = append(o.entries, e)
+	o.mu.Unlock()
+}
+
+// Len returns the count of recorded log entries.
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.entries)
+}
+
+// All returns a copy of all recorded log entries.
+func (o *ObservedLogs) All() []Entry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	entries := make([]Entry, len(o.entries))
+	copy(entries, o.entries)
+	return entries
+}
+
+// TakeAll returns all recorded log entries and clears them.
+func (o *ObservedLogs) TakeAll() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// FilterLevel returns ObservedLogs which has the entries with the level.
+func (o *ObservedLogs) FilterLevel(level string) *ObservedLogs {
+	return o.filter(func(e Entry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterLabel returns ObservedLogs which has the entries with a field
+// of the label and the value.
+func (o *ObservedLogs) FilterLabel(label, value string) *ObservedLogs {
+	return o.filter(func(e Entry) bool {
+		v, ok := e.Value(label)
+		return ok && v == value
+	})
+}
+
+func (o *ObservedLogs) filter(f func(e Entry) bool) *ObservedLogs {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var entries []Entry
+	for _, e := range o.entries {
+		if f(e) {
+			entries = append(entries, e)
+		}
+	}
+	return &ObservedLogs{entries: entries}
+}
+
+// AssertContains reports an error to t unless there is an entry with the
+// level and all fields of the labels and values in lv.
+// lv must be pairs of labels and values.
+func (o *ObservedLogs) AssertContains(t testing.TB, level string, lv ...string) bool {
+	t.Helper()
+	if len(lv)%2 == 1 {
+		panic("lv must be label and value pairs")
+	}
+	logs := o.FilterLevel(level)
+	for i := 0; i < len(lv); i += 2 {
+		logs = logs.FilterLabel(lv[i], lv[i+1])
+	}
+	if logs.Len() == 0 {
+		t.Errorf("no log entry with level=%s and %s in %v", level, formatLV(lv), o.All())
+		return false
+	}
+	return true
+}
+
+func formatLV(lv []string) string {
+	var b strings.Builder
+	for i := 0; i < len(lv); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(lv[i])
+		b.WriteByte('=')
+		b.WriteString(lv[i+1])
+	}
+	return b.String()
+}
+
+// NewTestLogger creates a logger which writes each log line with t.Log,
+// so logs are shown only for failed tests or with "go test -v".
+func NewTestLogger(t testing.TB, debugEnabled bool, options ...ltsvlog.Option) *ltsvlog.LTSVLogger {
+	return ltsvlog.NewLTSVLogger(testWriter{t}, debugEnabled, options...)
+}
+
+type testWriter struct {
+	t testing.TB
+}
+
+func (w testWriter) Write(p []byte) (n int, err error) {
+	w.t.Helper()
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,94 @@
+package ltsvlogtest
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ltsvlog "github.com/hnakamur/ltsvlog/v3"
+)
+
+func TestNew(t *testing.T) {
+	logger, logs := New(true)
+	logger.Debug().String("msg", "debug message").Log()
+	logger.Info().String("msg", "hello\tworld").Int("n", 1).Log()
+	logger.Info().String("msg", "goodbye").Log()
+	logger.Err(errors.New("some error"))
+
+	want := []Entry{
+		{Level: "Debug", Fields: []ltsvlog.Field{{Label: "msg", Value: "debug message"}}},
+		{Level: "Info", Fields: []ltsvlog.Field{{Label: "msg", Value: "hello\tworld"}, {Label: "n", Value: "1"}}},
+		{Level: "Info", Fields: []ltsvlog.Field{{Label: "msg", Value: "goodbye"}}},
+		{Level: "Error", Fields: []ltsvlog.Field{{Label: "err", Value: "some error"}}},
+	}
+	if got := logs.All(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	if got, want := logs.FilterLevel("Info").Len(), 2; got != want {
+		t.Errorf("FilterLevel count mismatch, got %d; want %d", got, want)
+	}
+	if got, want := logs.FilterLabel("msg", "goodbye").Len(), 1; got != want {
+		t.Errorf("FilterLabel count mismatch, got %d; want %d", got, want)
+	}
+	logs.AssertContains(t, "Info", "msg", "hello\tworld", "n", "1")
+	logs.AssertContains(t, "Error", "err", "some error")
+
+	if got, want := len(logs.TakeAll()), 4; got != want {
+		t.Errorf("TakeAll count mismatch, got %d; want %d", got, want)
+	}
+	if got, want := logs.Len(), 0; got != want {
+		t.Errorf("count mismatch after TakeAll, got %d; want %d", got, want)
+	}
+}
+
+func TestNew_DebugDisabled(t *testing.T) {
+	logger, logs := New(false, ltsvlog.SetEncoder(ltsvlog.NewJSONEncoder()))
+	logger.Debug().String("msg", "debug message").Log()
+	logger.Info().String("msg", "info message").Log()
+	if got, want := logs.Len(), 1; got != want {
+		t.Fatalf("count mismatch, got %d; want %d", got, want)
+	}
+	logs.AssertContains(t, "Info", "msg", "info message")
+}
+
+type recordingTB struct {
+	testing.TB
+	logs   []string
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Log(args ...interface{}) {
+	r.logs = append(r.logs, args[0].(string))
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, format)
+}
+
+func TestObservedLogs_AssertContains_Fail(t *testing.T) {
+	logger, logs := New(true)
+	logger.Info().String("msg", "hello").Log()
+
+	r := &recordingTB{}
+	if logs.AssertContains(r, "Info", "msg", "goodbye") {
+		t.Error("AssertContains must return false")
+	}
+	if len(r.errors) != 1 {
+		t.Errorf("error count mismatch, got %d; want 1", len(r.errors))
+	}
+}
+
+func TestNewTestLogger(t *testing.T) {
+	r := &recordingTB{}
+	logger := NewTestLogger(r, true, ltsvlog.SetTimeLabel(""))
+	logger.Info().String("msg", "hello").Log()
+
+	want := []string{"level:Info\tmsg:hello"}
+	if !reflect.DeepEqual(r.logs, want) {
+		t.Errorf("got %q; want %q", strings.Join(r.logs, "\n"), want)
+	}
+}
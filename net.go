@@ -0,0 +1,144 @@
+package ltsvlog
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// appendIP appends ip in the same format as net.IP.String without
+// allocation.
+func appendIP(buf []byte, ip net.IP) []byte {
+	if len(ip) == 0 {
+		return append(buf, "<nil>"...)
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		buf = append(buf, '?')
+		for _, b := range ip {
+			buf = append(buf, digits[b>>4], digits[b&0xF])
+		}
+		return buf
+	}
+	return addr.Unmap().AppendTo(buf)
+}
+
+// appendIPNet appends n in the same format as net.IPNet.String.
+func appendIPNet(buf []byte, n *net.IPNet) []byte {
+	if n == nil {
+		return append(buf, "<nil>"...)
+	}
+	ones, bits := n.Mask.Size()
+	if bits == 0 {
+		// The mask is not in the canonical form.
+		return append(buf, n.String()...)
+	}
+	buf = appendIP(buf, n.IP)
+	buf = append(buf, '/')
+	return strconv.AppendInt(buf, int64(ones), 10)
+}
+
+// SetRedactURLQuery returns the option function to add patterns of
+// names of URL query parameters whose values are redacted in values
+// written with Event.URL. The patterns are in the same format as the
+// ones of SetRedactLabels. The values are replaced with the mask set
+// with SetRedactMask.
+func SetRedactURLQuery(patterns ...string) Option {
+	return func(l *LTSVLogger) {
+		for _, p := range patterns {
+			l.redactQueryPatterns = append(l.redactQueryPatterns, strings.ToLower(p))
+		}
+	}
+}
+
+// appendURL appends u in the same format as url.URL.Redacted, that is,
+// the password is replaced with "xxxxx". The values of query parameters
+// whose names match one of queryPatterns are replaced with mask.
+// Unlike url.URL.String, the host is not escaped.
+func appendURL(buf []byte, u *url.URL, queryPatterns []string, mask string) []byte {
+	if u == nil {
+		return append(buf, "<nil>"...)
+	}
+	start := len(buf)
+	if u.Scheme != "" {
+		buf = append(buf, u.Scheme...)
+		buf = append(buf, ':')
+	}
+	path := u.EscapedPath()
+	if u.Opaque != "" {
+		buf = append(buf, u.Opaque...)
+	} else {
+		if u.Scheme != "" || u.Host != "" || u.User != nil {
+			if u.Host != "" || path != "" || u.User != nil {
+				buf = append(buf, "//"...)
+			}
+			if ui := u.User; ui != nil {
+				buf = append(buf, ui.Username()...)
+				if _, has := ui.Password(); has {
+					buf = append(buf, ":xxxxx"...)
+				}
+				buf = append(buf, '@')
+			}
+			buf = append(buf, u.Host...)
+		}
+		if path != "" && path[0] != '/' && u.Host != "" {
+			buf = append(buf, '/')
+		}
+		if len(buf) == start {
+			// A relative path whose first segment contains a colon must be
+			// prefixed with "./" not to be parsed as a scheme.
+			if i := strings.IndexByte(path, ':'); i > -1 && strings.IndexByte(path[:i], '/') == -1 {
+				buf = append(buf, "./"...)
+			}
+		}
+		buf = append(buf, path...)
+	}
+	if u.ForceQuery || u.RawQuery != "" {
+		buf = append(buf, '?')
+		buf = appendRedactedQuery(buf, u.RawQuery, queryPatterns, mask)
+	}
+	if u.Fragment != "" {
+		buf = append(buf, '#')
+		buf = append(buf, u.EscapedFragment()...)
+	}
+	return buf
+}
+
+func appendRedactedQuery(buf []byte, query string, patterns []string, mask string) []byte {
+	if len(patterns) == 0 {
+		return append(buf, query...)
+	}
+	for {
+		param := query
+		i := strings.IndexByte(query, '&')
+		if i != -1 {
+			param = query[:i]
+		}
+		if j := strings.IndexByte(param, '='); j != -1 && matchQueryName(patterns, param[:j]) {
+			buf = append(buf, param[:j+1]...)
+			buf = append(buf, mask...)
+		} else {
+			buf = append(buf, param...)
+		}
+		if i == -1 {
+			break
+		}
+		buf = append(buf, '&')
+		query = query[i+1:]
+	}
+	return buf
+}
+
+func matchQueryName(patterns []string, name string) bool {
+	if name, err := url.QueryUnescape(name); err == nil {
+		name = strings.ToLower(name)
+		for _, p := range patterns {
+			if matchGlob(p, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package ltsvlog
+
+const raceEnabled = false
@@ -0,0 +1,8 @@
+//go:build race
+
+package ltsvlog
+
+// raceEnabled is whether or not the race detector is enabled.
+// Allocation counts are not stable with the race detector, since
+// sync.Pool drops items randomly.
+const raceEnabled = true
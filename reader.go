@@ -0,0 +1,84 @@
+package ltsvlog
+
+import (
+	"errors"
+	"strings"
+)
+
+// Field is a pair of a label and a value in a LTSV line.
+type Field struct {
+	Label string
+	Value string
+}
+
+// ErrInvalidLine is the error returned from ParseLine when the line has
+// a field without a colon between the label and the value.
+var ErrInvalidLine = errors.New("ltsvlog: invalid LTSV line")
+
+// ErrInvalidEscape is the error returned when a value has a backslash
+// which is not followed by "n", "t" or "\\".
+var ErrInvalidEscape = errors.New("ltsvlog: invalid escape sequence")
+
+// ParseLine parses a line written by LTSVLogger with the LTSV encoder and
+// returns the fields in the order in the line. The trailing newline is
+// optional. Values are unescaped with UnescapeValue.
+func ParseLine(line string) ([]Field, error) {
+	line = strings.TrimSuffix(line, "\n")
+	if line == "" {
+		return nil, nil
+	}
+	var fields []Field
+	for {
+		var field string
+		i := strings.IndexByte(line, '\t')
+		if i == -1 {
+			field = line
+		} else {
+			field = line[:i]
+		}
+
+		j := strings.IndexByte(field, ':')
+		if j == -1 {
+			return nil, ErrInvalidLine
+		}
+		value, err := UnescapeValue(field[j+1:])
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, Field{Label: field[:j], Value: value})
+
+		if i == -1 {
+			return fields, nil
+		}
+		line = line[i+1:]
+	}
+}
+
+// UnescapeValue converts "\\n", "\\t", and "\\\\" in a LTSV value back to
+// newline, tab, and backslash characters respectively.
+func UnescapeValue(s string) (string, error) {
+	i := strings.IndexByte(s, '\\')
+	if i == -1 {
+		return s, nil
+	}
+	b := make([]byte, 0, len(s))
+	for ; i != -1; i = strings.IndexByte(s, '\\') {
+		b = append(b, s[:i]...)
+		if i+1 >= len(s) {
+			return "", ErrInvalidEscape
+		}
+		switch s[i+1] {
+		case 'n':
+			b = append(b, '\n')
+		case 't':
+			b = append(b, '\t')
+		case '\\':
+			b = append(b, '\\')
+		default:
+			return "", ErrInvalidEscape
+		}
+		s = s[i+2:]
+	}
+	b = append(b, s...)
+	return string(b), nil
+}
@@ -0,0 +1,50 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	testCases := []struct {
+		line    string
+		want    []Field
+		wantErr error
+	}{
+		{line: "", want: nil},
+		{line: "\n", want: nil},
+		{line: "a:b\n", want: []Field{{"a", "b"}}},
+		{line: "a:b\tc:\td:e:f", want: []Field{{"a", "b"}, {"c", ""}, {"d", "e:f"}}},
+		{line: `a:b\tc\nd\\e`, want: []Field{{"a", "b\tc\nd\\e"}}},
+		{line: "a:b\tc", wantErr: ErrInvalidLine},
+		{line: `a:b\`, wantErr: ErrInvalidEscape},
+		{line: `a:b\x`, wantErr: ErrInvalidEscape},
+	}
+	for _, tc := range testCases {
+		got, err := ParseLine(tc.line)
+		if err != tc.wantErr {
+			t.Errorf("line=%q, err mismatch, got=%v, want=%v", tc.line, err, tc.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("line=%q, got=%v, want=%v", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestParseLine_RoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""))
+	value := "{\n\t\"foo\": \"bar\\nbaz\"\n}\n"
+	logger.Info().String("json", value).Log()
+
+	got, err := ParseLine(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{{"level", "Info"}, {"json", value}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got=%q, want=%q", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package ltsvlog
+
+import (
+	"crypto/sha256"
+	"strings"
+	"sync"
+)
+
+const defaultRedactMask = "[REDACTED]"
+
+// SetRedactLabels returns the option function to add label patterns
+// whose values are redacted. A pattern is a label name, or a name with
+// "*" wildcards matching any sequence of characters like "*token*".
+// Patterns are matched case-insensitively. For fields in a namespace
+// started with Event.Namespace, patterns are matched against both the
+// label without the namespace prefix and the prefixed label, so both
+// "password" and "client.*" match "client.password".
+//
+// Redaction is applied at encode time to values appended with Event methods
+// and to labeled values of errors written with Err.
+// The values are replaced with the mask set with SetRedactMask, or the
+// hash of the values if SetRedactHash is enabled.
+func SetRedactLabels(patterns ...string) Option {
+	return func(l *LTSVLogger) {
+		for _, p := range patterns {
+			l.redactPatterns = append(l.redactPatterns, strings.ToLower(p))
+		}
+	}
+}
+
+// SetRedactMask returns the option function to set the mask for redacted
+// values. The default mask is "[REDACTED]".
+func SetRedactMask(mask string) Option {
+	return func(l *LTSVLogger) {
+		l.redactMask = mask
+	}
+}
+
+// SetRedactHash returns the option function to set whether or not
+// redacted values are replaced with hashes instead of the mask.
+// The hash is written as "sha256:" followed by the first 16 hex digits of
+// the SHA-256 hash of the encoded value, so you can still tell whether
+// two values are the same without revealing them.
+func SetRedactHash(enabled bool) Option {
+	return func(l *LTSVLogger) {
+		l.redactHash = enabled
+	}
+}
+
+// maxRedactCacheSize is the maximum count of labels cached in a redactor,
+// in order to avoid unbounded growth with dynamically created labels.
+const maxRedactCacheSize = 1024
+
+type redactor struct {
+	patterns []string
+	mask     string
+	hash     bool
+
+	mu    sync.RWMutex
+	cache map[string]bool
+}
+
+func newRedactor(patterns []string, mask string, hash bool) *redactor {
+	return &redactor{
+		patterns: patterns,
+		mask:     mask,
+		hash:     hash,
+		cache:    make(map[string]bool),
+	}
+}
+
+// match returns whether or not the value for label must be redacted.
+func (r *redactor) match(label string) bool {
+	r.mu.RLock()
+	matched, ok := r.cache[label]
+	r.mu.RUnlock()
+	if ok {
+		return matched
+	}
+
+	lower := strings.ToLower(label)
+	for _, p := range r.patterns {
+		if matchGlob(p, lower) {
+			matched = true
+			break
+		}
+	}
+
+	r.mu.Lock()
+	if len(r.cache) < maxRedactCacheSize {
+		r.cache[label] = matched
+	}
+	r.mu.Unlock()
+	return matched
+}
+
+// matchBytes is the same as match except that label is a byte slice.
+// It does not allocate memory if the result is cached.
+func (r *redactor) matchBytes(label []byte) bool {
+	r.mu.RLock()
+	matched, ok := r.cache[string(label)]
+	r.mu.RUnlock()
+	if ok {
+		return matched
+	}
+	return r.match(string(label))
+}
+
+// redact replaces the encoded value at buf[start:] with the mask or hash.
+func (r *redactor) redact(enc Encoder, buf []byte, start int) []byte {
+	if r.hash {
+		sum := sha256.Sum256(buf[start:])
+		buf = append(buf[:start], "sha256:"...)
+		for _, b := range sum[:8] {
+			buf = append(buf, digits[b>>4], digits[b&0xF])
+		}
+		return enc.EncodeStringAt(buf, start)
+	}
+	return enc.AppendString(buf[:start], r.mask)
+}
+
+// matchGlob returns whether or not s matches pattern which may contain
+// "*" wildcards.
+func matchGlob(pattern, s string) bool {
+	// star and next are the positions just after the last "*" in pattern
+	// and the corresponding position in s, to backtrack on mismatch.
+	star, next := -1, 0
+	p, i := 0, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, next = p+1, i
+			p++
+		case p < len(pattern) && pattern[p] == s[i]:
+			p++
+			i++
+		case star != -1:
+			next++
+			p, i = star, next
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
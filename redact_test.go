@@ -0,0 +1,96 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestSetRedactLabels(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []Option
+		f       func(l *LTSVLogger)
+		want    string
+	}{
+		{
+			name:    "mask",
+			options: []Option{SetRedactLabels("password", "*token*", "authorization")},
+			f: func(l *LTSVLogger) {
+				l.Info().String("user", "foo").String("password", "secret").
+					String("access_token", "abc").Int("Authorization", 123).
+					String("PASSWORD", "secret2").Log()
+			},
+			want: "level:Info\tuser:foo\tpassword:[REDACTED]\taccess_token:[REDACTED]\tAuthorization:[REDACTED]\tPASSWORD:[REDACTED]\n",
+		},
+		{
+			name:    "custom_mask",
+			options: []Option{SetRedactLabels("password"), SetRedactMask("***")},
+			f: func(l *LTSVLogger) {
+				l.Info().String("password", "secret").Log()
+			},
+			want: "level:Info\tpassword:***\n",
+		},
+		{
+			name:    "hash",
+			options: []Option{SetRedactLabels("password"), SetRedactHash(true)},
+			f: func(l *LTSVLogger) {
+				l.Info().String("password", "secret").Log()
+			},
+			// The first 8 bytes of sha256("secret").
+			want: "level:Info\tpassword:sha256:2bb80d537b1da3e3\n",
+		},
+		{
+			name:    "json",
+			options: []Option{SetRedactLabels("password"), SetEncoder(NewJSONEncoder())},
+			f: func(l *LTSVLogger) {
+				l.Info().String("password", "secret").Int("n", 1).Log()
+			},
+			want: `{"level":"Info","password":"[REDACTED]","n":1}` + "\n",
+		},
+		{
+			name:    "err_lv",
+			options: []Option{SetRedactLabels("*token")},
+			f: func(l *LTSVLogger) {
+				l.Err(errstack.WithLV(errors.New("some error")).String("reqID", "req1").String("apiToken", "abc"))
+			},
+			want: "level:Error\terr:some error\treqID:req1\tapiToken:[REDACTED]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			// We don't print time fields to make it easy to compare test results.
+			logger := NewLTSVLogger(buf, true, append(tc.options, SetTimeLabel(""))...)
+			tc.f(logger)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{pattern: "password", s: "password", want: true},
+		{pattern: "password", s: "password2", want: false},
+		{pattern: "*token*", s: "token", want: true},
+		{pattern: "*token*", s: "access_token_id", want: true},
+		{pattern: "*token*", s: "tokeen", want: false},
+		{pattern: "*_key", s: "api_key_key", want: true},
+		{pattern: "a*b*c", s: "abbc", want: true},
+		{pattern: "a*b*c", s: "acb", want: false},
+		{pattern: "*", s: "", want: true},
+	}
+	for _, tc := range testCases {
+		if got := matchGlob(tc.pattern, tc.s); got != tc.want {
+			t.Errorf("pattern=%s, s=%s, got %v; want %v", tc.pattern, tc.s, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package ltsvlog
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// SetSampler returns the option function to enable sampling of Debug and
+// Info level logs.
+//
+// Logs are grouped by the level and the value of the message label.
+// For each group, the first logs up to first in each second are written,
+// and after that every thereafter-th log is written. If thereafter is zero,
+// all logs after the first ones are dropped in that second.
+//
+// The counts of dropped logs are written as an Info level log like
+// "msg:log events dropped by sampler	debug_dropped:2	info_dropped:10"
+// at the first Debug or Info log in each second if any logs were dropped.
+// A timer is also set when a log is dropped, so the counts are written
+// a second later even if no more logs come.
+//
+// Sampling is done with atomic counters without locks. Groups are mapped
+// to a fixed number of counters by hashing, so different groups may rarely
+// share the same counter.
+func SetSampler(first, thereafter int) Option {
+	return func(l *LTSVLogger) {
+		l.sampler = &sampler{
+			first:      uint64(first),
+			thereafter: uint64(thereafter),
+		}
+	}
+}
+
+const (
+	samplerTick     = time.Second
+	samplerCounters = 4096
+)
+
+type sampler struct {
+	// counts must be the first field for 64-bit alignment of atomic
+	// operations on 32-bit platforms.
+	counts [2][samplerCounters]samplerCounter
+	// dropped is the counts of dropped logs for Debug and Info levels.
+	dropped    [2]uint64
+	nextReport int64
+	// timerSet is 1 if the timer to flush the counts of dropped logs is set.
+	timerSet   uint32
+	first      uint64
+	thereafter uint64
+}
+
+// samplerCounter is the count of logs in the current tick.
+// The upper 32 bits of state is the index of the tick since the Unix
+// epoch, and the lower 32 bits is the count, so that resetting the count
+// for a new tick and incrementing it are done with one atomic operation.
+type samplerCounter struct {
+	state uint64
+}
+
+func samplerLevelIndex(level string) int {
+	if level == "Debug" {
+		return 0
+	}
+	return 1
+}
+
+// sample returns whether or not the log of the level and the message
+// must be written.
+func (s *sampler) sample(now time.Time, level string, msg []byte) bool {
+	li := samplerLevelIndex(level)
+	c := &s.counts[li][fnv32a(msg)%samplerCounters]
+	n := c.inc(now.UnixNano())
+	if n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0) {
+		return true
+	}
+	atomic.AddUint64(&s.dropped[li], 1)
+	return false
+}
+
+// inc increments the counter and returns the new count.
+// The counter is reset if the tick has passed since the last reset.
+// Only the goroutine which succeeds in swapping the state for a new tick
+// resets the count, so no increments are lost.
+func (c *samplerCounter) inc(now int64) uint64 {
+	tick := uint64(now/int64(samplerTick)) << 32
+	for {
+		old := atomic.LoadUint64(&c.state)
+		var n uint64
+		if old&^math.MaxUint32 == tick {
+			n = old&math.MaxUint32 + 1
+			if n > math.MaxUint32 {
+				return math.MaxUint32
+			}
+		} else {
+			n = 1
+		}
+		if atomic.CompareAndSwapUint64(&c.state, old, tick|n) {
+			return n
+		}
+	}
+}
+
+// takeDropped returns the counts of dropped logs and resets them if it is
+// time to report them. It returns false if it is not time to report, or
+// there are no dropped logs.
+func (s *sampler) takeDropped(now time.Time) (debugDropped, infoDropped uint64, ok bool) {
+	t := now.UnixNano()
+	next := atomic.LoadInt64(&s.nextReport)
+	if t < next || !atomic.CompareAndSwapInt64(&s.nextReport, next, t+int64(samplerTick)) {
+		return 0, 0, false
+	}
+	debugDropped = atomic.SwapUint64(&s.dropped[0], 0)
+	infoDropped = atomic.SwapUint64(&s.dropped[1], 0)
+	return debugDropped, infoDropped, debugDropped > 0 || infoDropped > 0
+}
+
+// droppedOne is called after a log is dropped. It returns true if the caller
+// must set the timer to call flushDropped.
+func (s *sampler) droppedOne() bool {
+	return atomic.CompareAndSwapUint32(&s.timerSet, 0, 1)
+}
+
+// flushDropped returns the counts of dropped logs and resets them.
+// It returns false if there are no dropped logs.
+func (s *sampler) flushDropped() (debugDropped, infoDropped uint64, ok bool) {
+	// Clear timerSet before taking the counts, so that logs dropped after
+	// taking them set a new timer.
+	atomic.StoreUint32(&s.timerSet, 0)
+	debugDropped = atomic.SwapUint64(&s.dropped[0], 0)
+	infoDropped = atomic.SwapUint64(&s.dropped[1], 0)
+	return debugDropped, infoDropped, debugDropped > 0 || infoDropped > 0
+}
+
+// setSamplerTimer sets the timer to write the counts of dropped logs.
+func (l *LTSVLogger) setSamplerTimer() {
+	time.AfterFunc(samplerTick, func() {
+		if debugDropped, infoDropped, ok := l.sampler.flushDropped(); ok {
+			l.writeSamplerDropped(debugDropped, infoDropped)
+		}
+	})
+}
+
+func (l *LTSVLogger) reportSamplerDropped(now time.Time) {
+	debugDropped, infoDropped, ok := l.sampler.takeDropped(now)
+	if !ok {
+		return
+	}
+	l.writeSamplerDropped(debugDropped, infoDropped)
+}
+
+func (l *LTSVLogger) writeSamplerDropped(debugDropped, infoDropped uint64) {
+	enc := l.encoder
+	buf := make([]byte, 0, 256)
+	buf = l.appendPrefixFunc(buf, "Info")
+	if !l.msgNoLabel {
+		buf = enc.AppendKey(buf, l.msgLabel)
+	}
+	buf = enc.AppendString(buf, "log events dropped by sampler")
+	buf = enc.AppendEndField(buf)
+	buf = enc.AppendKey(buf, "debug_dropped")
+	buf = enc.AppendUint64(buf, debugDropped)
+	buf = enc.AppendEndField(buf)
+	buf = enc.AppendKey(buf, "info_dropped")
+	buf = enc.AppendUint64(buf, infoDropped)
+	buf = enc.AppendEndField(buf)
+	buf = enc.AppendEndRecord(buf)
+	_, _ = l.writer.Write(buf)
+}
+
+// fnv32a returns the FNV-1a hash of b.
+func fnv32a(b []byte) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for _, c := range b {
+		h ^= uint32(c)
+		h *= prime32
+	}
+	return h
+}
@@ -0,0 +1,115 @@
+package ltsvlog
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetSampler(t *testing.T) {
+	now := time.Date(2017, 5, 7, 22, 13, 59, 0, time.UTC)
+	var mu sync.Mutex
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+
+	// The timer to write the dropped counts may write to buf later.
+	buf := new(syncBuffer)
+	// We don't print time fields to make it easy to compare test results.
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetClock(clock), SetSampler(2, 3))
+	for i := 0; i < 8; i++ {
+		logger.Info().String("msg", "hello").Int("i", i).Log()
+		logger.Debug().Int("i", i).String("msg", "hello").Log()
+	}
+	logger.Info().String("msg", "goodbye").Log()
+	want := "level:Info\tmsg:hello\ti:0\n" +
+		"level:Debug\ti:0\tmsg:hello\n" +
+		"level:Info\tmsg:hello\ti:1\n" +
+		"level:Debug\ti:1\tmsg:hello\n" +
+		"level:Info\tmsg:hello\ti:4\n" +
+		"level:Debug\ti:4\tmsg:hello\n" +
+		"level:Info\tmsg:hello\ti:7\n" +
+		"level:Debug\ti:7\tmsg:hello\n" +
+		"level:Info\tmsg:goodbye\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	buf.Reset()
+	advance(time.Second)
+	logger.Info().String("msg", "hello").Log()
+	want = "level:Info\tmsg:log events dropped by sampler\tdebug_dropped:4\tinfo_dropped:4\n" +
+		"level:Info\tmsg:hello\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	buf.Reset()
+	advance(time.Second)
+	logger.Info().String("msg", "hello").Log()
+	want = "level:Info\tmsg:hello\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestSetSampler_Concurrent(t *testing.T) {
+	buf := new(syncBuffer)
+	w := NewSwitchableWriter(buf)
+	// Use a fixed clock so that the counters are not reset at the tick.
+	now := time.Date(2017, 5, 7, 22, 13, 59, 0, time.UTC)
+	clock := func() time.Time { return now }
+	logger := NewLTSVLogger(w, true, SetTimeLabel(""), SetClock(clock), SetSampler(10, 0))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info().String("msg", "hello").Log()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := strings.Count(buf.String(), "\n"); got != 10 {
+		t.Errorf("unexpected line count %d", got)
+	}
+}
+
+func TestSetSampler_Timer(t *testing.T) {
+	buf := new(syncBuffer)
+	logger := NewLTSVLogger(buf, true, SetTimeLabel(""), SetSampler(1, 0))
+
+	// A burst of logs, and then silence.
+	for i := 0; i < 3; i++ {
+		logger.Info().String("msg", "hello").Log()
+	}
+	want := "level:Info\tmsg:hello\n" +
+		"level:Info\tmsg:log events dropped by sampler\tdebug_dropped:0\tinfo_dropped:2\n"
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() != want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func BenchmarkSampler(b *testing.B) {
+	logger := NewLTSVLogger(io.Discard, true, SetSampler(100, 100))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info().String("msg", "hello").Int("n", 1).Log()
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package ltsvlog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/hnakamur/errstack"
+)
+
+// StackFormat is the format of call stacks of errors.
+type StackFormat int
+
+const (
+	// StackFormatSingle is the default format, which writes all frames
+	// separated by a space with the "stack" label.
+	StackFormatSingle StackFormat = iota
+	// StackFormatIndexed writes each frame with the label "stack"
+	// followed by the index starting at 0, like "stack0", "stack1".
+	StackFormatIndexed
+)
+
+// SetStackFormat returns the option function to set the format of call
+// stacks of errors.
+func SetStackFormat(format StackFormat) Option {
+	return func(l *LTSVLogger) {
+		l.stackFormat = format
+	}
+}
+
+// SetStackCompact returns the option function to set whether or not
+// file paths of stack frames are shortened to the package import path
+// and the file name, like "github.com/hnakamur/ltsvlog/v3/log.go", which
+// is the path relative to GOPATH/src or the module root of the package.
+// For packages whose import path has no slash like "main", the last
+// directory and the file name are written instead, like "app/main.go".
+func SetStackCompact(enabled bool) Option {
+	return func(l *LTSVLogger) {
+		l.stackCompact = enabled
+	}
+}
+
+// SetStackMaxFrames returns the option function to set the maximum count
+// of stack frames written for an error. Frames exceeding the count are
+// dropped from the bottom of the stack.
+// The default is 0, that is, all frames are written.
+func SetStackMaxFrames(n int) Option {
+	return func(l *LTSVLogger) {
+		l.stackMaxFrames = n
+	}
+}
+
+// SetErrStackDepth returns the option function to set the maximum count
+// of stack frames which are captured at the call site of Err, ErrEvent
+// and Event.Err when the error has no call stack, for example errors
+// created with errors.New or fmt.Errorf.
+// The captured stack is written with the "stack" label in the same
+// format as stacks of errors created with github.com/hnakamur/errstack.
+// The default depth is 0, that is, stacks are not captured.
+func SetErrStackDepth(depth int) Option {
+	return func(l *LTSVLogger) {
+		l.errStackDepth = depth
+	}
+}
+
+// SetErrStackFilter returns the option function to set the function to
+// filter captured stack frames. Frames for which filter returns false
+// are dropped and do not count toward the depth set with
+// SetErrStackDepth. The default filter drops frames of the runtime and
+// testing packages. If filter is nil, all frames are kept.
+func SetErrStackFilter(filter func(f errstack.Frame) bool) Option {
+	return func(l *LTSVLogger) {
+		l.errStackFilter = filter
+	}
+}
+
+func defaultErrStackFilter(f errstack.Frame) bool {
+	return !strings.HasPrefix(f.Name, "runtime.") && !strings.HasPrefix(f.Name, "testing.")
+}
+
+// maxErrStackFrames is the maximum count of stack frames looked up for
+// capturing a stack, including the ones dropped by the filter.
+const maxErrStackFrames = 256
+
+// captureStack returns the stack frames of the caller.
+// The skip argument is the count of stack frames to skip, with 0
+// identifying the caller of captureStack.
+func (l *LTSVLogger) captureStack(skip int) []errstack.Frame {
+	n := l.errStackDepth + 16
+	if n > maxErrStackFrames {
+		n = maxErrStackFrames
+	}
+	pcs := make([]uintptr, n)
+	n = runtime.Callers(skip+2+l.callerSkip, pcs)
+	if n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	var ff []errstack.Frame
+	for len(ff) < l.errStackDepth {
+		frame, more := frames.Next()
+		f := errstack.Frame{Name: frame.Function, Line: frame.Line, Path: frame.File}
+		if l.errStackFilter == nil || l.errStackFilter(f) {
+			ff = append(ff, f)
+		}
+		if !more {
+			break
+		}
+	}
+	return ff
+}
+
+// appendStack appends the stack fields.
+func (e *Event) appendStack(ff []errstack.Frame) {
+	l := e.logger
+	if l.stackMaxFrames > 0 && len(ff) > l.stackMaxFrames {
+		ff = ff[:l.stackMaxFrames]
+	}
+	if l.stackFormat == StackFormatIndexed {
+		for i, f := range ff {
+			e.appendKey(stackLabel(i))
+			start := len(e.buf)
+			e.buf = appendFrame(e.buf, f, l.stackCompact)
+			e.buf = l.encoder.EncodeStringAt(e.buf, start)
+			e.endField()
+		}
+		return
+	}
+
+	e.appendKey("stack")
+	start := len(e.buf)
+	for i, f := range ff {
+		if i > 0 {
+			e.buf = append(e.buf, ' ')
+		}
+		e.buf = appendFrame(e.buf, f, l.stackCompact)
+	}
+	e.buf = l.encoder.EncodeStringAt(e.buf, start)
+	e.endField()
+}
+
+// stackLabels are the preallocated labels for StackFormatIndexed.
+var stackLabels = func() []string {
+	labels := make([]string, 32)
+	for i := range labels {
+		labels[i] = "stack" + strconv.Itoa(i)
+	}
+	return labels
+}()
+
+func stackLabel(i int) string {
+	if i < len(stackLabels) {
+		return stackLabels[i]
+	}
+	return "stack" + strconv.Itoa(i)
+}
+
+// appendFrame appends a stack frame in the same format as
+// errstack.Frame.String. If compact is true, the file path is shortened.
+func appendFrame(buf []byte, f errstack.Frame, compact bool) []byte {
+	buf = append(buf, f.Name...)
+	buf = append(buf, '@')
+	if compact {
+		buf = appendCompactPath(buf, f)
+	} else {
+		buf = append(buf, f.Path...)
+	}
+	buf = append(buf, ':')
+	return strconv.AppendInt(buf, int64(f.Line), 10)
+}
+
+// appendCompactPath appends the import path of the package of the
+// function followed by the file name, like "example.com/pkg/file.go".
+// The last directory and the file name are appended if the package
+// cannot be determined from the function name, or the package path has
+// no slash like "main", since the path does not match the directory.
+func appendCompactPath(buf []byte, f errstack.Frame) []byte {
+	// The function name is like "example.com/pkg.Func" or
+	// "example.com/pkg.(*Type).Method", and dots in the last element of
+	// the package path are escaped as "%2e" in function names, which are
+	// unescaped here.
+	slash := strings.LastIndexByte(f.Name, '/')
+	dot := strings.IndexByte(f.Name[slash+1:], '.')
+	file := strings.LastIndexByte(f.Path, '/')
+	if slash == -1 || dot == -1 || file == -1 {
+		return append(buf, shortFilePath(f.Path)...)
+	}
+	buf = append(buf, f.Name[:slash+1]...)
+	for elem := f.Name[slash+1 : slash+1+dot]; elem != ""; {
+		i := strings.Index(elem, "%2e")
+		if i == -1 {
+			buf = append(buf, elem...)
+			break
+		}
+		buf = append(buf, elem[:i]...)
+		buf = append(buf, '.')
+		elem = elem[i+3:]
+	}
+	return append(buf, f.Path[file:]...)
+}
@@ -0,0 +1,134 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hnakamur/errstack"
+)
+
+func TestSetErrStackDepth(t *testing.T) {
+	const fn = `github\.com/hnakamur/ltsvlog/v3\.TestSetErrStackDepth\.func\d+@[^\t]*/stack_test\.go:\d+`
+	testCases := []struct {
+		name string
+		opts []Option
+		f    func(l *LTSVLogger)
+		want string
+	}{
+		{
+			name: "disabled",
+			f:    func(l *LTSVLogger) { l.Err(errors.New("some error")) },
+			want: "^level:Error\terr:some error\n$",
+		},
+		{
+			name: "err",
+			opts: []Option{SetErrStackDepth(1)},
+			f:    func(l *LTSVLogger) { l.Err(errors.New("some error")) },
+			want: "^level:Error\terr:some error\tstack:" + fn + "\n$",
+		},
+		{
+			name: "errEvent",
+			opts: []Option{SetErrStackDepth(1)},
+			f:    func(l *LTSVLogger) { l.ErrEvent(errors.New("some error")).Log() },
+			want: "^level:Error\terr:some error\tstack:" + fn + "\n$",
+		},
+		{
+			name: "eventErr",
+			opts: []Option{SetErrStackDepth(1)},
+			f:    func(l *LTSVLogger) { l.Info().Err("err", errors.New("some error")).Log() },
+			want: "^level:Info\terr:some error\tstack:" + fn + "\n$",
+		},
+		{
+			name: "filtered",
+			opts: []Option{SetErrStackDepth(10)},
+			f:    func(l *LTSVLogger) { l.Err(errors.New("some error")) },
+			want: "^level:Error\terr:some error\tstack:" + fn + ` github\.com/hnakamur/ltsvlog/v3\.TestSetErrStackDepth\.func\d+@[^\t ]+\n$`,
+		},
+		{
+			name: "customFilter",
+			opts: []Option{
+				SetErrStackDepth(10),
+				SetErrStackFilter(func(f errstack.Frame) bool {
+					return !strings.HasPrefix(f.Name, "runtime.")
+				}),
+			},
+			f:    func(l *LTSVLogger) { l.Err(errors.New("some error")) },
+			want: "^level:Error\terr:some error\tstack:" + fn + ` [^\t]* testing\.tRunner@[^\t]+\n$`,
+		},
+		{
+			name: "errstack",
+			opts: []Option{SetErrStackDepth(1), SetErrStackFilter(func(f errstack.Frame) bool { return false })},
+			f:    func(l *LTSVLogger) { l.Err(errstack.New("some error")) },
+			want: "^level:Error\terr:some error\tstack:" + fn + "( [^\t]+)?\n$",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLTSVLogger(&buf, false, append([]Option{SetTimeLabel("")}, tc.opts...)...)
+			tc.f(logger)
+			if got := buf.String(); !regexp.MustCompile(tc.want).MatchString(got) {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+type testStackError struct {
+	err   error
+	stack []errstack.Frame
+}
+
+func (e *testStackError) Error() string           { return e.err.Error() }
+func (e *testStackError) Unwrap() error           { return e.err }
+func (e *testStackError) Stack() []errstack.Frame { return e.stack }
+
+func TestSetStackFormat(t *testing.T) {
+	err := &testStackError{
+		err: errors.New("some error"),
+		stack: []errstack.Frame{
+			{Name: "example.com/app/db.(*Conn).Query", Path: "/home/user/go/pkg/mod/example.com/app@v1.0.0/db/conn.go", Line: 12},
+			{Name: "gopkg.in/yaml%2ev2.(*decoder).unmarshal", Path: "/home/user/go/pkg/mod/gopkg.in/yaml.v2@v2.4.0/decode.go", Line: 7},
+			{Name: "main.main", Path: "/src/app/main.go", Line: 34},
+			{Name: "", Path: "/src/app/other.go", Line: 56},
+		},
+	}
+	testCases := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "default",
+			want: "level:Error\terr:some error\tstack:example.com/app/db.(*Conn).Query@/home/user/go/pkg/mod/example.com/app@v1.0.0/db/conn.go:12 gopkg.in/yaml%2ev2.(*decoder).unmarshal@/home/user/go/pkg/mod/gopkg.in/yaml.v2@v2.4.0/decode.go:7 main.main@/src/app/main.go:34 @/src/app/other.go:56\n",
+		},
+		{
+			name: "compact",
+			opts: []Option{SetStackCompact(true)},
+			want: "level:Error\terr:some error\tstack:example.com/app/db.(*Conn).Query@example.com/app/db/conn.go:12 gopkg.in/yaml%2ev2.(*decoder).unmarshal@gopkg.in/yaml.v2/decode.go:7 main.main@app/main.go:34 @app/other.go:56\n",
+		},
+		{
+			name: "indexed",
+			opts: []Option{SetStackFormat(StackFormatIndexed), SetStackCompact(true)},
+			want: "level:Error\terr:some error\tstack0:example.com/app/db.(*Conn).Query@example.com/app/db/conn.go:12\tstack1:gopkg.in/yaml%2ev2.(*decoder).unmarshal@gopkg.in/yaml.v2/decode.go:7\tstack2:main.main@app/main.go:34\tstack3:@app/other.go:56\n",
+		},
+		{
+			name: "maxFrames",
+			opts: []Option{SetStackFormat(StackFormatIndexed), SetStackMaxFrames(1)},
+			want: "level:Error\terr:some error\tstack0:example.com/app/db.(*Conn).Query@/home/user/go/pkg/mod/example.com/app@v1.0.0/db/conn.go:12\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLTSVLogger(&buf, false, append([]Option{SetTimeLabel("")}, tc.opts...)...)
+			logger.Err(err)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("log unmatch,\n got=%q,\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ltsvlog
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeFormat is a predefined format of the time field.
+type TimeFormat int
+
+const (
+	// TimeFormatUTCMicro is the default format, which is the same as
+	// "2006-01-02T15:04:05.000000Z" in UTC.
+	TimeFormatUTCMicro TimeFormat = iota
+	// TimeFormatUTCMilli is the same as "2006-01-02T15:04:05.000Z" in UTC.
+	TimeFormatUTCMilli
+	// TimeFormatUTCNano is the same as "2006-01-02T15:04:05.000000000Z" in UTC.
+	TimeFormatUTCNano
+	// TimeFormatLocalMicro is the same as "2006-01-02T15:04:05.000000Z07:00"
+	// in the local time zone.
+	TimeFormatLocalMicro
+	// TimeFormatLocalMilli is the same as "2006-01-02T15:04:05.000Z07:00"
+	// in the local time zone.
+	TimeFormatLocalMilli
+	// TimeFormatLocalNano is the same as "2006-01-02T15:04:05.000000000Z07:00"
+	// in the local time zone.
+	TimeFormatLocalNano
+	// TimeFormatUnix is the number of seconds elapsed since the Unix epoch.
+	TimeFormatUnix
+	// TimeFormatUnixMilli is the number of milliseconds elapsed since the Unix epoch.
+	TimeFormatUnixMilli
+)
+
+// SetTimeFormat returns the option function to set the format of
+// the time field to one of the predefined formats.
+// These formats are written with hand-rolled formatters which are
+// faster than time.Time.Format.
+// If format is not one of the predefined formats, TimeFormatUTCMicro
+// is used.
+func SetTimeFormat(format TimeFormat) Option {
+	if format < 0 || int(format) >= len(timeFormatters) {
+		format = TimeFormatUTCMicro
+	}
+	return func(l *LTSVLogger) {
+		l.timeFormatter = &timeFormatters[format]
+	}
+}
+
+// SetTimeLayout returns the option function to set the format of the
+// time field to the layout in the Go standard time package.
+// The time is converted to loc before formatting. If loc is nil,
+// UTC is used.
+func SetTimeLayout(layout string, loc *time.Location) Option {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(l *LTSVLogger) {
+		l.timeFormatter = &timeFormatter{
+			appendTime: func(buf []byte, t time.Time) []byte {
+				return t.In(loc).AppendFormat(buf, layout)
+			},
+		}
+	}
+}
+
+type timeFormatter struct {
+	appendTime func(buf []byte, t time.Time) []byte
+	// numeric is true if the formatted values are numbers
+	// which do not need to be quoted.
+	numeric bool
+}
+
+var timeFormatters = [...]timeFormatter{
+	TimeFormatUTCMicro: {appendTime: appendUTCTime},
+	TimeFormatUTCMilli: {appendTime: func(buf []byte, t time.Time) []byte {
+		return appendRFC3339Time(buf, t.UTC(), 3)
+	}},
+	TimeFormatUTCNano: {appendTime: func(buf []byte, t time.Time) []byte {
+		return appendRFC3339Time(buf, t.UTC(), 9)
+	}},
+	TimeFormatLocalMicro: {appendTime: func(buf []byte, t time.Time) []byte {
+		return appendRFC3339Time(buf, t.Local(), 6)
+	}},
+	TimeFormatLocalMilli: {appendTime: func(buf []byte, t time.Time) []byte {
+		return appendRFC3339Time(buf, t.Local(), 3)
+	}},
+	TimeFormatLocalNano: {appendTime: func(buf []byte, t time.Time) []byte {
+		return appendRFC3339Time(buf, t.Local(), 9)
+	}},
+	TimeFormatUnix: {appendTime: func(buf []byte, t time.Time) []byte {
+		return strconv.AppendInt(buf, t.Unix(), 10)
+	}, numeric: true},
+	TimeFormatUnixMilli: {appendTime: func(buf []byte, t time.Time) []byte {
+		return strconv.AppendInt(buf, t.UnixMilli(), 10)
+	}, numeric: true},
+}
+
+// appendValue appends the formatted time value encoded with enc.
+func (f *timeFormatter) appendValue(enc Encoder, buf []byte, t time.Time) []byte {
+	start := len(buf)
+	buf = f.appendTime(buf, t)
+	if f.numeric {
+		return buf
+	}
+	return enc.EncodeStringAt(buf, start)
+}
+
+// appendRFC3339Time appends t in the RFC3339 format with the fraction of
+// the second in fracDigits digits, which must be 3, 6 or 9.
+// The time zone is written as "Z" if the offset is zero.
+func appendRFC3339Time(buf []byte, t time.Time, fracDigits int) []byte {
+	tmp := []byte("0000-00-00T00:00:00.000000000+00:00")
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	itoa(tmp[:4], year, 4)
+	itoa(tmp[5:7], int(month), 2)
+	itoa(tmp[8:10], day, 2)
+	itoa(tmp[11:13], hour, 2)
+	itoa(tmp[14:16], min, 2)
+	itoa(tmp[17:19], sec, 2)
+	frac := t.Nanosecond()
+	for i := fracDigits; i < 9; i++ {
+		frac /= 10
+	}
+	itoa(tmp[20:20+fracDigits], frac, fracDigits)
+	n := 20 + fracDigits
+
+	_, offset := t.Zone()
+	if offset == 0 {
+		tmp[n] = 'Z'
+		return append(buf, tmp[:n+1]...)
+	}
+	tmp[n] = '+'
+	if offset < 0 {
+		tmp[n] = '-'
+		offset = -offset
+	}
+	offset /= 60
+	itoa(tmp[n+1:n+3], offset/60, 2)
+	tmp[n+3] = ':'
+	itoa(tmp[n+4:n+6], offset%60, 2)
+	return append(buf, tmp[:n+6]...)
+}
@@ -0,0 +1,103 @@
+package ltsvlog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeFormatters(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	nst := time.FixedZone("NST", -(3*60*60 + 30*60))
+	val := time.Date(2017, 5, 7, 22, 13, 59, 987654321, time.UTC)
+
+	testCases := []struct {
+		name   string
+		format TimeFormat
+		loc    *time.Location
+		want   string
+	}{
+		{name: "utc_micro", format: TimeFormatUTCMicro, loc: jst, want: "2017-05-07T22:13:59.987654Z"},
+		{name: "utc_milli", format: TimeFormatUTCMilli, loc: jst, want: "2017-05-07T22:13:59.987Z"},
+		{name: "utc_nano", format: TimeFormatUTCNano, loc: jst, want: "2017-05-07T22:13:59.987654321Z"},
+		{name: "unix", format: TimeFormatUnix, loc: jst, want: "1494195239"},
+		{name: "unix_milli", format: TimeFormatUnixMilli, loc: jst, want: "1494195239987"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(timeFormatters[tc.format].appendTime(nil, val.In(tc.loc)))
+			if got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+
+	rfc3339TestCases := []struct {
+		name       string
+		val        time.Time
+		fracDigits int
+		want       string
+	}{
+		{name: "utc", val: val, fracDigits: 6, want: "2017-05-07T22:13:59.987654Z"},
+		{name: "positive_offset", val: val.In(jst), fracDigits: 3, want: "2017-05-08T07:13:59.987+09:00"},
+		{name: "negative_offset", val: val.In(nst), fracDigits: 9, want: "2017-05-07T18:43:59.987654321-03:30"},
+	}
+	for _, tc := range rfc3339TestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(appendRFC3339Time(nil, tc.val, tc.fracDigits))
+			if got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+			if want := tc.val.Format(time.RFC3339Nano[:20] + "000000000"[:tc.fracDigits] + "Z07:00"); got != want {
+				t.Errorf("got %s; want %s in time.Format", got, want)
+			}
+		})
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	now := time.Date(2017, 5, 7, 22, 13, 59, 987654321, time.UTC)
+	clock := func() time.Time { return now }
+
+	testCases := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{
+			name:    "unix_milli_json",
+			options: []Option{SetTimeFormat(TimeFormatUnixMilli), SetEncoder(NewJSONEncoder())},
+			want:    `{"time":1494195239987,"level":"Info","msg":"hello"}` + "\n",
+		},
+		{
+			name:    "utc_nano",
+			options: []Option{SetTimeFormat(TimeFormatUTCNano)},
+			want:    "time:2017-05-07T22:13:59.987654321Z\tlevel:Info\tmsg:hello\n",
+		},
+		{
+			name:    "invalid_format",
+			options: []Option{SetTimeFormat(TimeFormat(100))},
+			want:    "time:2017-05-07T22:13:59.987654Z\tlevel:Info\tmsg:hello\n",
+		},
+		{
+			name:    "layout",
+			options: []Option{SetTimeLayout(time.RFC1123, nil)},
+			want:    "time:Sun, 07 May 2017 22:13:59 UTC\tlevel:Info\tmsg:hello\n",
+		},
+		{
+			name:    "layout_json",
+			options: []Option{SetTimeLayout(time.RFC3339, time.FixedZone("JST", 9*60*60)), SetEncoder(NewJSONEncoder())},
+			want:    `{"time":"2017-05-08T07:13:59+09:00","level":"Info","msg":"hello"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := NewLTSVLogger(buf, true, append(tc.options, SetClock(clock))...)
+			logger.Info().String("msg", "hello").Log()
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}